@@ -0,0 +1,108 @@
+package material
+
+import (
+	"context"
+	"iter"
+	"runtime"
+	"sync"
+)
+
+// BatchResult is the result of a source generated by GenerateBatch.
+type BatchResult struct {
+	Colors *Colors
+	Err    error
+}
+
+// GenerateBatch generates colors of every source concurrently with at most
+// Settings.Workers goroutines. The results are yielded with the index of their
+// source in the order they are generated, which is not the order of sources.
+//
+// Every source is yielded exactly once unless the loop is stopped. If the
+// context of the options is Done, the remaining sources are yielded with the
+// context error. Stopping the loop cancels the sources being generated and
+// waits for the goroutines to exit.
+//
+// Options are shared by all sources, so the quantizer must be safe for
+// concurrent use.
+func GenerateBatch(
+	sources []Source,
+	options ...Option,
+) iter.Seq2[int, BatchResult] {
+	return func(yield func(int, BatchResult) bool) {
+		cfg := newSettings(options)
+		ctx, cancel := context.WithCancel(cfg.Context)
+		defer cancel()
+
+		workers := cfg.Workers
+		if workers <= 0 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+		workers = min(workers, len(sources))
+
+		type result struct {
+			index int
+			BatchResult
+		}
+
+		// stop is closed when the loop is stopped
+		stop := make(chan struct{})
+		jobs := make(chan int)
+		results := make(chan result)
+
+		go func() {
+			defer close(jobs)
+			for i := range sources {
+				select {
+				case jobs <- i:
+				case <-stop:
+					return
+				}
+			}
+		}()
+
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					c := *cfg
+					c.Context = ctx
+					colors, err := generateSource(sources[i], &c)
+					select {
+					case results <- result{i, BatchResult{colors, err}}:
+					case <-stop:
+						return
+					}
+				}
+			}()
+		}
+
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		for r := range results {
+			if !yield(r.index, r.BatchResult) {
+				close(stop)
+				cancel()
+				wg.Wait()
+				return
+			}
+		}
+	}
+}
+
+// generateSource generates colors of src. Returns the context error without
+// reading src if the context of cfg is Done.
+func generateSource(src Source, cfg *Settings) (*Colors, error) {
+	if err := cfg.Context.Err(); err != nil {
+		return nil, err
+	}
+	colors, err := src()
+	if err != nil {
+		return nil, err
+	}
+	return generate(colors, nil, cfg)
+}
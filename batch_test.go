@@ -0,0 +1,87 @@
+package material
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+func batchSources(n int) []Source {
+	sources := make([]Source, n)
+	for i := range sources {
+		sources[i] = FromColor(color.ARGBFromRGB(uint8(i*7), 0x44, 0xFF))
+	}
+	return sources
+}
+
+func TestGenerateBatch(t *testing.T) {
+	sources := batchSources(20)
+	errSource := errors.New("source failed")
+	sources[5] = func() ([]color.ARGB, error) { return nil, errSource }
+
+	for _, workers := range []int{0, 1, 4, 100} {
+		t.Run(fmt.Sprint(workers), func(t *testing.T) {
+			seen := make([]bool, len(sources))
+			for i, r := range GenerateBatch(sources, WithWorkers(workers)) {
+				if seen[i] {
+					t.Fatalf("source %d is yielded twice", i)
+				}
+				seen[i] = true
+
+				if i == 5 {
+					if !errors.Is(r.Err, errSource) {
+						t.Errorf("source 5 error = %v, want %v", r.Err, errSource)
+					}
+					continue
+				}
+				if r.Err != nil {
+					t.Fatalf("source %d failed: %v", i, r.Err)
+				}
+
+				want, _ := Generate(sources[i])
+				if r.Colors.Primary != want.Primary {
+					t.Errorf("source %d primary = %s, want %s",
+						i, r.Colors.Primary, want.Primary)
+				}
+			}
+
+			for i, ok := range seen {
+				if !ok {
+					t.Errorf("source %d is not yielded", i)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateBatchStop(t *testing.T) {
+	n := 0
+	for range GenerateBatch(batchSources(100), WithWorkers(4)) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("loop ran %d times, want 3", n)
+	}
+}
+
+func TestGenerateBatchContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	n := 0
+	for _, r := range GenerateBatch(batchSources(10), WithContext(ctx)) {
+		n++
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("error = %v, want %v", r.Err, context.Canceled)
+		}
+	}
+	if n != 10 {
+		t.Errorf("GenerateBatch() yielded %d results, want 10", n)
+	}
+}
@@ -0,0 +1,90 @@
+package material
+
+import (
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/dynamic"
+)
+
+// ContrastLevels returns the standard, medium and high contrast levels
+// generated in a Bundle.
+func ContrastLevels() []float64 {
+	return []float64{0, 0.5, 1}
+}
+
+// Bundle is a set of colors generated from a single source color.
+type Bundle struct {
+	Source     color.ARGB  `json:"source"`
+	Candidates []Candidate `json:"candidates"`
+	Colors     []*Colors   `json:"colors"`
+}
+
+// GenerateBundle generates light and dark colors of every ContrastLevels for
+// each variant. The source is quantized and scored only once. If variants is
+// empty, the variant of the options is used. Dark and contrast settings of the
+// options are ignored.
+func GenerateBundle(
+	src Source,
+	variants []dynamic.Variant,
+	options ...Option,
+) (*Bundle, error) {
+	pixels, err := src()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := newSettings(options)
+
+	source, candidates, err := sourceColor(cfg, pixels, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(variants) == 0 {
+		variants = []dynamic.Variant{cfg.Variant}
+	}
+
+	levels := ContrastLevels()
+	bundle := &Bundle{
+		Source:     source,
+		Candidates: candidates,
+		Colors:     make([]*Colors, 0, len(variants)*2*len(levels)),
+	}
+	for _, variant := range variants {
+		for _, dark := range []bool{false, true} {
+			for _, contrast := range levels {
+				if err := cfg.Context.Err(); err != nil {
+					return nil, err
+				}
+
+				c := *cfg
+				c.Variant = variant
+				c.Dark = dark
+				c.Contrast = contrast
+				scheme, err := newScheme(source, &c)
+				if err != nil {
+					return nil, err
+				}
+				colors := createColors(scheme, c.Custom)
+				bundle.Colors = append(bundle.Colors, colors)
+			}
+		}
+	}
+
+	return bundle, nil
+}
+
+// Get returns the colors of given variant, mode and contrast level. Returns nil
+// if bundle doesn't contain such colors.
+func (b *Bundle) Get(
+	variant dynamic.Variant,
+	dark bool,
+	contrast float64,
+) *Colors {
+	for _, c := range b.Colors {
+		s := c.Scheme
+		if s.Variant == variant && s.Dark == dark && s.Contrast == contrast {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package material
+
+import (
+	"testing"
+
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/dynamic"
+)
+
+func TestGenerateBundle(t *testing.T) {
+	pixels := []string{"#FF1100", "#11FF00", "#1111FF", "#3F3F11", "#007755"}
+	src := FromHexes(pixels)
+	custom := WithCustomColor("success", color.ARGBFromHexMust("#00FF00"))
+
+	variants := []dynamic.Variant{VariantTonalSpot, VariantVibrant}
+	bundle, err := GenerateBundle(src, variants, custom)
+	if err != nil {
+		t.Fatalf("GenerateBundle() failed: %v", err)
+	}
+
+	want := len(variants) * 2 * len(ContrastLevels())
+	if len(bundle.Colors) != want {
+		t.Fatalf("bundle has %d colors, want %d", len(bundle.Colors), want)
+	}
+
+	for _, variant := range variants {
+		for _, dark := range []bool{false, true} {
+			for _, contrast := range ContrastLevels() {
+				got := bundle.Get(variant, dark, contrast)
+				if got == nil {
+					t.Fatalf("bundle has no colors for %v/%v/%v",
+						variant, dark, contrast)
+				}
+
+				want, err := Generate(
+					FromARGB([]color.ARGB{bundle.Source}),
+					custom,
+					WithVariant(variant),
+					WithDark(dark),
+					WithContrast(contrast),
+				)
+				if err != nil {
+					t.Fatalf("failed to generate colors: %v", err)
+				}
+
+				if got.Primary != want.Primary ||
+					got.Surface != want.Surface ||
+					got.CustomColors["success"] != want.CustomColors["success"] {
+					t.Errorf("bundle colors for %v/%v/%v differ from Generate()",
+						variant, dark, contrast)
+				}
+			}
+		}
+	}
+
+	if bundle.Get(VariantContent, false, 0) != nil {
+		t.Error("Get() returned colors for variant not in bundle")
+	}
+}
+
+func TestGenerateBundleDefaultVariant(t *testing.T) {
+	bundle, err := GenerateBundle(FromHex("#0044FF"), nil)
+	if err != nil {
+		t.Fatalf("GenerateBundle() failed: %v", err)
+	}
+
+	if len(bundle.Colors) != 2*len(ContrastLevels()) {
+		t.Fatalf("bundle has %d colors, want %d",
+			len(bundle.Colors), 2*len(ContrastLevels()))
+	}
+	if bundle.Get(VariantExpressive, true, 1) == nil {
+		t.Error("bundle has no colors for the default variant")
+	}
+}
@@ -0,0 +1,107 @@
+package material
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/quantizer"
+	"github.com/Nadim147c/material/v3/score"
+)
+
+// defaultMaxColors is the default max number of quantized colors
+const defaultMaxColors = 5
+
+var errCandidateRange = errors.New("candidate is out of range")
+
+// Candidate is a ranked source color candidate
+type Candidate struct {
+	Color color.ARGB `json:"color"`
+	// Population is the number of source colors quantized into Color. It is
+	// zero for the scoring fallback color.
+	Population int `json:"population"`
+}
+
+// sourceColor ranks the candidates of colors and returns the selected
+// candidate. Colors are quantized and scored unless there is only one color.
+// Colors are weighted by weights unless weights is nil.
+func sourceColor(
+	cfg *Settings,
+	colors []color.ARGB,
+	weights []float64,
+) (color.ARGB, []Candidate, error) {
+	if len(colors) == 0 {
+		return 0, nil, errNoColorFound
+	}
+
+	var candidates []Candidate
+	if len(colors) == 1 {
+		candidates = []Candidate{{Color: colors[0], Population: 1}}
+	} else {
+		maxColors := cfg.MaxColors
+		if maxColors <= 0 {
+			maxColors = defaultMaxColors
+		}
+
+		q := cfg.Quantizer
+		if q == nil {
+			var opts []quantizer.Option
+			if cfg.Seed != nil {
+				opts = append(opts, quantizer.WithSeed(*cfg.Seed))
+			}
+			if cfg.AlphaWeighting {
+				opts = append(opts, quantizer.WithAlphaWeighting())
+			}
+			q = quantizer.Celebi{Options: opts}
+		}
+
+		quantized, err := quantize(cfg.Context, q, colors, weights, maxColors)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(quantized) == 0 {
+			return 0, nil, errNoColorFound
+		}
+
+		scored := score.Score(quantized, cfg.Score...)
+		candidates = make([]Candidate, 0, len(scored))
+		for _, c := range scored {
+			candidates = append(candidates, Candidate{
+				Color:      c,
+				Population: quantized[c],
+			})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return 0, nil, errNoColorFound
+	}
+
+	if cfg.Candidate < 0 || cfg.Candidate >= len(candidates) {
+		return 0, nil, fmt.Errorf(
+			"%w: %d is not in [0, %d)",
+			errCandidateRange, cfg.Candidate, len(candidates),
+		)
+	}
+	return candidates[cfg.Candidate].Color, candidates, nil
+}
+
+// quantize quantizes colors with q. Colors are weighted by weights unless
+// weights is nil.
+func quantize(
+	ctx context.Context,
+	q quantizer.Quantizer,
+	colors []color.ARGB,
+	weights []float64,
+	maxColors int,
+) (quantizer.QuantizedMap, error) {
+	if weights == nil {
+		return q.Quantize(ctx, colors, maxColors)
+	}
+	wq, ok := q.(quantizer.WeightedQuantizer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errUnweightedQuantizer, q)
+	}
+	return wq.QuantizeWeighted(ctx, colors, weights, maxColors)
+}
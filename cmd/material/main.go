@@ -0,0 +1,177 @@
+// Command material generates material you colors from an image, a hex color or
+// a raw RGB stream and prints them as JSON.
+//
+// Usage:
+//
+//	material [flags] <image|hex|->
+//
+// The input can be a path to a PNG, JPEG or GIF image, a hex color (e.g.
+// "#0044ff") or "-" to read raw r, g, b bytes from stdin (e.g. ffmpeg
+// rawvideo output with -pix_fmt rgb24).
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Nadim147c/material/v3"
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/dynamic"
+)
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "material:", err)
+		os.Exit(1)
+	}
+}
+
+// customFlags collects repeated -custom flags. Each value is formatted as
+// name=#RRGGBB or name=#RRGGBB:ratio, where ratio blends the color with the
+// primary color.
+type customFlags []material.Option
+
+var _ flag.Value = (*customFlags)(nil)
+
+func (c *customFlags) String() string {
+	return ""
+}
+
+func (c *customFlags) Set(value string) error {
+	name, hex, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid custom color %q: expected name=color", value)
+	}
+
+	hex, ratio, blend := strings.Cut(hex, ":")
+	argb, err := color.ARGBFromHex(hex)
+	if err != nil {
+		return fmt.Errorf("invalid custom color %q: %w", value, err)
+	}
+
+	if !blend {
+		*c = append(*c, material.WithCustomColor(name, argb))
+		return nil
+	}
+
+	r, err := strconv.ParseFloat(ratio, 64)
+	if err != nil {
+		return fmt.Errorf("invalid blend ratio %q: %w", ratio, err)
+	}
+	*c = append(*c, material.WithCustomColorBlend(name, argb, r))
+	return nil
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("material", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: material [flags] <image|hex|->")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+
+	var (
+		variant  = material.VariantExpressive
+		version  = material.Version2025
+		platform = material.PlatformPhone
+		custom   customFlags
+		options  []material.Option
+	)
+
+	fs.TextVar(&variant, "variant", variant, "scheme variant: "+
+		strings.Join(dynamic.VariantNames(), ", "))
+	fs.TextVar(&version, "version", version, "material spec version: "+
+		strings.Join(dynamic.VersionNames(), ", "))
+	fs.TextVar(&platform, "platform", platform, "target platform: "+
+		strings.Join(dynamic.PlatformNames(), ", "))
+	contrast := fs.Float64("contrast", 0, "contrast level in range [-1, 1]")
+	dark := fs.Bool("dark", false, "generate dark scheme")
+	indent := fs.Bool("indent", false, "indent json output")
+	fs.Var(&custom, "custom",
+		"custom color as name=#RRGGBB or name=#RRGGBB:ratio (repeatable)")
+	fs.Func("seed", "seed for reproducible quantization", func(v string) error {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		options = append(options, material.WithSeed(seed))
+		return nil
+	})
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one input")
+	}
+
+	src, err := source(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+
+	options = append(options,
+		material.WithVariant(variant),
+		material.WithVersion(version),
+		material.WithPlatform(platform),
+		material.WithContrast(*contrast),
+		material.WithDark(*dark),
+	)
+	options = append(options, custom...)
+
+	colors, err := material.GenerateWeighted(src, options...)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(stdout)
+	if *indent {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(colors)
+}
+
+// source returns material.WeightedSource for given input. "-" streams raw rgb
+// bytes from stdin, existing files are decoded as images and everything else
+// is parsed as hex color.
+func source(input string, stdin io.Reader) (material.WeightedSource, error) {
+	if input == "-" {
+		ctx := context.Background()
+		return material.FromStream(ctx, stdin, material.PixelFormatRGB24), nil
+	}
+
+	_, err := os.Stat(input)
+	if errors.Is(err, os.ErrNotExist) {
+		if _, err := color.ARGBFromHex(input); err != nil {
+			return nil, fmt.Errorf("%q is neither a file nor a hex color", input)
+		}
+		return unweighted(material.FromHex(input)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unweighted(material.FromImageFile(input)), nil
+}
+
+// unweighted returns src as material.WeightedSource without weights.
+func unweighted(src material.Source) material.WeightedSource {
+	return func() ([]color.ARGB, []float64, error) {
+		colors, err := src()
+		return colors, nil, err
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Nadim147c/material/v3"
+)
+
+func TestRun(t *testing.T) {
+	rgb := bytes.Repeat([]byte{0x00, 0x44, 0xFF, 0xFF, 0x11, 0x00}, 100)
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin io.Reader
+	}{
+		{"hex", []string{"#0044ff"}, nil},
+		{"image", []string{"-dark", "../../quantizer/gophar.jpg"}, nil},
+		{"stdin", []string{"-variant", "tonal_spot", "-"}, bytes.NewReader(rgb)},
+		{"seed", []string{"-seed", "42", "../../quantizer/gophar.jpg"}, nil},
+		{
+			"custom",
+			[]string{
+				"-version", "2021",
+				"-contrast", "0.5",
+				"-custom", "green=#00FF00",
+				"-custom", "red=#FF0000:0.5",
+				"#0044ff",
+			},
+			nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if err := run(tc.args, tc.stdin, &stdout, &stderr); err != nil {
+				t.Fatalf("run(%v) failed: %v: %s", tc.args, err, &stderr)
+			}
+
+			var colors material.Colors
+			if err := json.Unmarshal(stdout.Bytes(), &colors); err != nil {
+				t.Fatalf("failed to decode output: %v", err)
+			}
+			if colors.Primary == 0 {
+				t.Errorf("output has no primary color: %s", &stdout)
+			}
+		})
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"not-a-color"},
+		{"-variant", "unknown", "#0044ff"},
+		{"-custom", "green", "#0044ff"},
+		{"-custom", "green=#00FF00:x", "#0044ff"},
+		{"-seed", "x", "#0044ff"},
+	}
+
+	for _, args := range tests {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			err := run(args, nil, io.Discard, io.Discard)
+			if err == nil {
+				t.Errorf("run(%v) returned nil error", args)
+			}
+		})
+	}
+}
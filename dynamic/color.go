@@ -110,20 +110,51 @@ func FromPalette(name string, palette TonalPaletteFunc, tone ToneFunc) *Color {
 }
 
 // GetArgb returns the ARGB value for the DynamicColor in the given scheme.
+// Results of named colors are cached by the scheme.
 func (dc *Color) GetArgb(scheme *Scheme) color.ARGB {
-	return dc.GetHct(scheme).ToARGB()
+	c := scheme.resolved()
+	if c == nil || dc.Name == "" {
+		return dc.GetHct(scheme).ToARGB()
+	}
+	return memoize(&c.argbs, keyOf(dc), func() color.ARGB {
+		return dc.GetHct(scheme).ToARGB()
+	})
 }
 
 // GetHct returns the HCT color for the DynamicColor in the given scheme.
+// Results of named colors are cached by the scheme.
 func (dc *Color) GetHct(scheme *Scheme) color.Hct {
+	c := scheme.resolved()
+	if c == nil || dc.Name == "" {
+		return dc.getHct(scheme)
+	}
+	return memoize(&c.hcts, keyOf(dc), func() color.Hct {
+		return dc.getHct(scheme)
+	})
+}
+
+func (dc *Color) getHct(scheme *Scheme) color.Hct {
+	dc = scheme.roleOf(dc)
 	if scheme.Version == Version2025 {
 		return ColorCalculation2025.GetHct(scheme, dc)
 	}
 	return ColorCalculation2021.GetHct(scheme, dc)
 }
 
-// GetTone retuns Tone for the dynamic color using given scheme.
+// GetTone retuns Tone for the dynamic color using given scheme. Results of
+// named colors are cached by the scheme.
 func (dc *Color) GetTone(scheme *Scheme) float64 {
+	c := scheme.resolved()
+	if c == nil || dc.Name == "" {
+		return dc.getTone(scheme)
+	}
+	return memoize(&c.tones, keyOf(dc), func() float64 {
+		return dc.getTone(scheme)
+	})
+}
+
+func (dc *Color) getTone(scheme *Scheme) float64 {
+	dc = scheme.roleOf(dc)
 	if scheme.Version == Version2025 {
 		return ColorCalculation2025.GetTone(scheme, dc)
 	}
@@ -0,0 +1,144 @@
+package dynamic
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Nadim147c/material/v3/palettes"
+)
+
+// customTemplate is the name of the color family that custom colors are
+// resolved like
+const customTemplate = "tertiary"
+
+// CustomColor is a user defined color of a scheme, e.g. a brand color. Its
+// roles are resolved like the tertiary roles of the scheme with Palette in
+// place of the tertiary palette, so they follow the variant, contrast level,
+// platform and version of the scheme, and meet the same contrast requirements
+// as the built-in roles.
+type CustomColor struct {
+	// Name is the name of the color. Its roles are named like the tertiary
+	// roles with Name in place of tertiary, e.g. on_<name>_container, so Name
+	// must be unique among the roles of the scheme.
+	Name string
+	// Palette produces the colors of the roles.
+	Palette palettes.TonalPalette
+}
+
+// NewCustomColor returns a CustomColor named name with the palette p
+func NewCustomColor(name string, p palettes.TonalPalette) CustomColor {
+	return CustomColor{Name: name, Palette: p}
+}
+
+// Roles returns the roles of c for the spec by role name: <name>,
+// on_<name>, <name>_container, on_<name>_container, <name>_fixed,
+// <name>_fixed_dim, on_<name>_fixed, on_<name>_fixed_variant and <name>_dim.
+// Roles which are not available in the spec, e.g. <name>_dim in 2021 spec,
+// are omitted.
+func (c CustomColor) Roles(spec MaterialColorSpec) map[string]*Color {
+	// Every role is created once and referenced by the other roles, so each
+	// role is resolved only once by the scheme cache.
+	templates := []*Color{
+		spec.Tertiary(),
+		spec.OnTertiary(),
+		spec.TertiaryContainer(),
+		spec.OnTertiaryContainer(),
+		spec.TertiaryFixed(),
+		spec.TertiaryFixedDim(),
+		spec.OnTertiaryFixed(),
+		spec.OnTertiaryFixedVariant(),
+		spec.TertiaryDim(),
+	}
+	templates = slices.DeleteFunc(templates, func(t *Color) bool {
+		return t == nil
+	})
+	replaced := make(map[roleKey]*Color, len(templates))
+	for _, t := range templates {
+		replaced[keyOf(t)] = &Color{}
+	}
+
+	roles := make(map[string]*Color, len(templates))
+	for _, t := range templates {
+		r := replaced[keyOf(t)]
+		*r = *c.role(t, replaced)
+		roles[r.Name] = r
+	}
+	return roles
+}
+
+// role returns the role of c resolved like the tertiary role t. All functions
+// of t are called with the scheme using the palette of c, and the tertiary
+// roles referenced by t are replaced by the roles of c in replaced.
+func (c CustomColor) role(t *Color, replaced map[roleKey]*Color) *Color {
+	replace := func(dc *Color) *Color {
+		if dc == nil || !strings.Contains(dc.Name, customTemplate) {
+			return dc
+		}
+		if r, ok := replaced[keyOf(dc)]; ok {
+			return r
+		}
+		return c.role(dc, replaced)
+	}
+
+	r := &Color{
+		Name: strings.Replace(t.Name, customTemplate, c.Name, 1),
+		Palette: func(*Scheme) palettes.TonalPalette {
+			return c.Palette
+		},
+		Tone: func(s *Scheme) float64 {
+			return t.Tone(c.scheme(s))
+		},
+		IsBackground: t.IsBackground,
+	}
+	if t.ChromaMultiplier != nil {
+		r.ChromaMultiplier = func(s *Scheme) float64 {
+			return t.ChromaMultiplier(c.scheme(s))
+		}
+	}
+	if t.Background != nil {
+		r.Background = func(s *Scheme) *Color {
+			return replace(t.Background(c.scheme(s)))
+		}
+	}
+	if t.SecondBackground != nil {
+		r.SecondBackground = func(s *Scheme) *Color {
+			return replace(t.SecondBackground(c.scheme(s)))
+		}
+	}
+	if t.ContrastCurve != nil {
+		r.ContrastCurve = func(s *Scheme) *ContrastCurve {
+			return t.ContrastCurve(c.scheme(s))
+		}
+	}
+	if t.ToneDeltaPair != nil {
+		r.ToneDeltaPair = func(s *Scheme) *ToneDeltaPair {
+			pair := t.ToneDeltaPair(c.scheme(s))
+			if pair == nil {
+				return nil
+			}
+			p := *pair
+			p.RoleA = replace(p.RoleA)
+			p.RoleB = replace(p.RoleB)
+			return &p
+		}
+	}
+	return r
+}
+
+// scheme returns s with the palette of c as the tertiary palette. The scheme
+// is created once per custom color if s has a cache.
+func (c CustomColor) scheme(s *Scheme) *Scheme {
+	create := func() *Scheme {
+		custom := new(Scheme)
+		*custom = *s
+		custom.TertiaryPalette = c.Palette
+		custom.cache = &schemeCache{owner: custom}
+		return custom
+	}
+
+	cache := s.resolved()
+	if cache == nil {
+		return create()
+	}
+	return memoize(&cache.customs, c, create)
+}
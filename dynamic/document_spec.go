@@ -0,0 +1,589 @@
+package dynamic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Nadim147c/material/v3/palettes"
+)
+
+// highestSurface is the background name of the highest surface of the scheme,
+// see MaterialColorSpec.HighestSurface
+const highestSurface = "highest_surface"
+
+// SpecDocument is a declarative material color spec, e.g.
+//
+//	{
+//	  "base": "2025",
+//	  "roles": {
+//	    "primary": {
+//	      "palette": "primary",
+//	      "tone": {"light": 35, "dark": 85},
+//	      "is_background": true,
+//	      "background": "highest_surface",
+//	      "contrast_curve": [3, 4.5, 7, 7],
+//	      "tone_delta_pair": {
+//	        "role_a": "primary_container",
+//	        "role_b": "primary",
+//	        "delta": 10,
+//	        "polarity": "nearer"
+//	      }
+//	    },
+//	    "primary_dim": null
+//	  }
+//	}
+//
+// Roles of the document replace the roles of the base spec with the same name,
+// and roles which are null are removed. Other roles are the roles of the base
+// spec. Roles of the base spec which reference a replaced role, e.g. as
+// background, use the role of the document.
+type SpecDocument struct {
+	// Base is the version of the spec the document is based on. Schemes using
+	// the spec should have the same version.
+	Base Version `json:"base"`
+	// Roles is the roles of the document by role name, see Scheme.ToColorMap
+	// for the names.
+	Roles map[string]*RoleDefinition `json:"roles"`
+}
+
+// RoleDefinition is a declarative role of a SpecDocument. Roles are referenced
+// by their names.
+type RoleDefinition struct {
+	// Palette is the palette of the role: primary, secondary, tertiary,
+	// neutral, neutral_variant or error.
+	Palette string `json:"palette"`
+	// Tone is the tone of the role before contrast adjustments.
+	Tone ModeTone `json:"tone"`
+	// ChromaMultiplier multiplies the chroma of the palette. Only used by 2025
+	// spec. Zero doesn't change the chroma.
+	ChromaMultiplier float64 `json:"chroma_multiplier,omitempty"`
+	// IsBackground indicates that the role is used as background of others.
+	IsBackground bool `json:"is_background,omitempty"`
+	// Background is the role the role has to contrast with, or
+	// highest_surface for the highest surface of the scheme. Requires
+	// ContrastCurve.
+	Background string `json:"background,omitempty"`
+	// SecondBackground is the second role the role has to contrast with.
+	SecondBackground string `json:"second_background,omitempty"`
+	// ContrastCurve is the required contrast ratio with the background at
+	// low, normal, medium and high contrast levels.
+	ContrastCurve []float64 `json:"contrast_curve,omitempty"`
+	// ToneDeltaPair is the tone difference constraint with another role.
+	ToneDeltaPair *ToneDeltaPairDefinition `json:"tone_delta_pair,omitempty"`
+}
+
+// ModeTone is a tone for light and dark mode.
+type ModeTone struct {
+	Light float64 `json:"light"`
+	Dark  float64 `json:"dark"`
+}
+
+// ToneDeltaPairDefinition is a declarative ToneDeltaPair. One of the roles
+// must be the role the pair is defined for.
+type ToneDeltaPairDefinition struct {
+	RoleA        string       `json:"role_a"`
+	RoleB        string       `json:"role_b"`
+	Delta        float64      `json:"delta"`
+	Polarity     TonePolarity `json:"polarity"`
+	StayTogether bool         `json:"stay_together,omitempty"`
+	Constraint   Constraint   `json:"constraint,omitempty"`
+}
+
+// DocumentSpec is a MaterialColorSpec created from a SpecDocument.
+type DocumentSpec struct {
+	base  MaterialColorSpec
+	doc   SpecDocument
+	roles map[string]*Color
+	// replaced is the roles of the spec by the role of the base spec they
+	// replace
+	replaced map[roleKey]*Color
+}
+
+var _ VersionedSpec = (*DocumentSpec)(nil)
+
+// ReadSpec decodes a SpecDocument from r and creates a DocumentSpec from it.
+func ReadSpec(r io.Reader) (*DocumentSpec, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var doc SpecDocument
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return NewDocumentSpec(doc)
+}
+
+// NewDocumentSpec creates a DocumentSpec from doc. Returns an error if doc
+// has unknown roles, palettes or references.
+func NewDocumentSpec(doc SpecDocument) (*DocumentSpec, error) {
+	var base MaterialColorSpec
+	switch doc.Base {
+	case Version2021:
+		base = &MaterialSpec2021{}
+	case Version2025:
+		base = &MaterialSpec2025{}
+	default:
+		return nil, fmt.Errorf("base %d is %w", doc.Base, ErrInvalidVersion)
+	}
+
+	s := &DocumentSpec{base: base, doc: doc, roles: specColorMap(base)}
+	for name, def := range doc.Roles {
+		if _, ok := s.roles[name]; !ok {
+			return nil, fmt.Errorf("unknown role %q", name)
+		}
+		if def == nil {
+			s.roles[name] = nil
+			continue
+		}
+
+		role, err := s.role(name, def)
+		if err != nil {
+			return nil, fmt.Errorf("role %q: %w", name, err)
+		}
+		s.roles[name] = role
+	}
+
+	for name, role := range s.roles {
+		s.roles[name] = s.pair(role)
+	}
+
+	// Roles of the 2025 spec extend the roles of the 2021 spec, which are
+	// referenced by the 2021 roles.
+	bases := []MaterialColorSpec{base}
+	if doc.Base == Version2025 {
+		bases = append(bases, MaterialSpec2021{})
+	}
+	s.replaced = map[roleKey]*Color{}
+	for _, b := range bases {
+		for name, c := range specColorMap(b) {
+			r := s.roles[name]
+			if c != nil && r != nil && keyOf(r) != keyOf(c) {
+				s.replaced[keyOf(c)] = r
+			}
+		}
+	}
+	return s, nil
+}
+
+// Version returns the version of the spec the document is based on.
+func (s *DocumentSpec) Version() Version {
+	return s.doc.Base
+}
+
+// Document returns the document of the spec.
+func (s *DocumentSpec) Document() SpecDocument {
+	return s.doc
+}
+
+// role creates the role named name from def
+func (s *DocumentSpec) role(name string, def *RoleDefinition) (*Color, error) {
+	palette, err := specPalette(def.Palette)
+	if err != nil {
+		return nil, err
+	}
+
+	tone := def.Tone
+	role := &Color{
+		Name:    name,
+		Palette: palette,
+		Tone: func(s *Scheme) float64 {
+			if s.Dark {
+				return tone.Dark
+			}
+			return tone.Light
+		},
+		IsBackground: def.IsBackground,
+	}
+
+	if m := def.ChromaMultiplier; m != 0 {
+		role.ChromaMultiplier = func(*Scheme) float64 { return m }
+	}
+
+	if (def.Background == "") != (def.ContrastCurve == nil) {
+		return nil, errors.New(
+			"background and contrast curve must be set together",
+		)
+	}
+	if def.Background != "" {
+		if role.Background, err = s.reference(def.Background); err != nil {
+			return nil, err
+		}
+		c := def.ContrastCurve
+		if len(c) != 4 {
+			return nil, fmt.Errorf("contrast curve has %d values, want 4",
+				len(c))
+		}
+		curve := NewContrastCurve(c[0], c[1], c[2], c[3])
+		role.ContrastCurve = func(*Scheme) *ContrastCurve { return curve }
+	}
+	if def.SecondBackground != "" {
+		role.SecondBackground, err = s.reference(def.SecondBackground)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p := def.ToneDeltaPair; p != nil {
+		if p.RoleA != name && p.RoleB != name {
+			return nil, errors.New("tone delta pair doesn't contain the role")
+		}
+		a, err := s.reference(p.RoleA)
+		if err != nil {
+			return nil, err
+		}
+		b, err := s.reference(p.RoleB)
+		if err != nil {
+			return nil, err
+		}
+		role.ToneDeltaPair = func(s *Scheme) *ToneDeltaPair {
+			return NewToneDeltaPair(a(s), b(s), p.Delta, p.Polarity,
+				p.StayTogether, p.Constraint)
+		}
+	}
+
+	return role, nil
+}
+
+// reference returns ColorFunc of the role named name. The role is looked up
+// when the function is called, so roles can reference each other.
+func (s *DocumentSpec) reference(name string) (ColorFunc, error) {
+	if name == highestSurface {
+		return s.HighestSurface, nil
+	}
+
+	if _, ok := s.roles[name]; !ok {
+		return nil, fmt.Errorf("unknown role %q", name)
+	}
+	if r, ok := s.doc.Roles[name]; ok && r == nil {
+		return nil, fmt.Errorf("role %q is removed", name)
+	}
+	return func(*Scheme) *Color { return s.lookup(name) }, nil
+}
+
+// lookup returns a copy of the role named name, so the roles of the spec
+// aren't modified through the returned colors. Returns nil if the role is
+// removed.
+func (s *DocumentSpec) lookup(name string) *Color {
+	r := s.roles[name]
+	if r == nil {
+		return nil
+	}
+	c := *r
+	return &c
+}
+
+// pair returns c with the roles of its tone delta pair replaced by the roles
+// of the spec
+func (s *DocumentSpec) pair(c *Color) *Color {
+	if c == nil || c.ToneDeltaPair == nil {
+		return c
+	}
+	p := *c
+	p.ToneDeltaPair = func(scheme *Scheme) *ToneDeltaPair {
+		pair := c.ToneDeltaPair(scheme)
+		if pair == nil {
+			return nil
+		}
+		tdp := *pair
+		tdp.RoleA = s.resolve(tdp.RoleA)
+		tdp.RoleB = s.resolve(tdp.RoleB)
+		return &tdp
+	}
+	return &p
+}
+
+// resolve returns the role of the spec which replaces the base spec role c.
+// Returns c if c isn't a replaced role of the base spec.
+func (s *DocumentSpec) resolve(c *Color) *Color {
+	if c == nil {
+		return nil
+	}
+	if r, ok := s.replaced[keyOf(c)]; ok {
+		return s.lookup(r.Name)
+	}
+	return c
+}
+
+// roleOf returns the role of the spec of d which replaces the base spec role
+// dc, so the roles of a base spec resolve the roles which are replaced by a
+// DocumentSpec, e.g. as background, to the roles of the document. Returns dc
+// for other colors and specs.
+func (d *Scheme) roleOf(dc *Color) *Color {
+	if s, ok := d.MaterialColor.(*DocumentSpec); ok {
+		return s.resolve(dc)
+	}
+	return dc
+}
+
+// specPalette returns TonalPaletteFunc of the palette named name
+func specPalette(name string) (TonalPaletteFunc, error) {
+	switch name {
+	case "primary":
+		return func(s *Scheme) palettes.TonalPalette {
+			return s.PrimaryPalette
+		}, nil
+	case "secondary":
+		return func(s *Scheme) palettes.TonalPalette {
+			return s.SecondaryPalette
+		}, nil
+	case "tertiary":
+		return func(s *Scheme) palettes.TonalPalette {
+			return s.TertiaryPalette
+		}, nil
+	case "neutral":
+		return func(s *Scheme) palettes.TonalPalette {
+			return s.NeutralPalette
+		}, nil
+	case "neutral_variant":
+		return func(s *Scheme) palettes.TonalPalette {
+			return s.NeutralVariantPalette
+		}, nil
+	case "error":
+		return func(s *Scheme) palettes.TonalPalette {
+			return s.ErrorPalette
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown palette %q", name)
+	}
+}
+
+// HighestSurface returns the highest surface role of the spec.
+func (s *DocumentSpec) HighestSurface(scheme *Scheme) *Color {
+	return s.resolve(s.base.HighestSurface(scheme))
+}
+
+//revive:disable:exported
+
+func (s *DocumentSpec) Background() *Color {
+	return s.lookup("background")
+}
+
+func (s *DocumentSpec) Error() *Color {
+	return s.lookup("error")
+}
+
+func (s *DocumentSpec) ErrorContainer() *Color {
+	return s.lookup("error_container")
+}
+
+func (s *DocumentSpec) ErrorDim() *Color {
+	return s.lookup("error_dim")
+}
+
+func (s *DocumentSpec) InverseOnSurface() *Color {
+	return s.lookup("inverse_on_surface")
+}
+
+func (s *DocumentSpec) InversePrimary() *Color {
+	return s.lookup("inverse_primary")
+}
+
+func (s *DocumentSpec) InverseSurface() *Color {
+	return s.lookup("inverse_surface")
+}
+
+func (s *DocumentSpec) NeutralPaletteKeyColor() *Color {
+	return s.lookup("neutral_palette_key_color")
+}
+
+func (s *DocumentSpec) NeutralVariantPaletteKeyColor() *Color {
+	return s.lookup("neutral_variant_palette_key_color")
+}
+
+func (s *DocumentSpec) OnBackground() *Color {
+	return s.lookup("on_background")
+}
+
+func (s *DocumentSpec) OnError() *Color {
+	return s.lookup("on_error")
+}
+
+func (s *DocumentSpec) OnErrorContainer() *Color {
+	return s.lookup("on_error_container")
+}
+
+func (s *DocumentSpec) OnPrimary() *Color {
+	return s.lookup("on_primary")
+}
+
+func (s *DocumentSpec) OnPrimaryContainer() *Color {
+	return s.lookup("on_primary_container")
+}
+
+func (s *DocumentSpec) OnPrimaryFixed() *Color {
+	return s.lookup("on_primary_fixed")
+}
+
+func (s *DocumentSpec) OnPrimaryFixedVariant() *Color {
+	return s.lookup("on_primary_fixed_variant")
+}
+
+func (s *DocumentSpec) OnSecondary() *Color {
+	return s.lookup("on_secondary")
+}
+
+func (s *DocumentSpec) OnSecondaryContainer() *Color {
+	return s.lookup("on_secondary_container")
+}
+
+func (s *DocumentSpec) OnSecondaryFixed() *Color {
+	return s.lookup("on_secondary_fixed")
+}
+
+func (s *DocumentSpec) OnSecondaryFixedVariant() *Color {
+	return s.lookup("on_secondary_fixed_variant")
+}
+
+func (s *DocumentSpec) OnSurface() *Color {
+	return s.lookup("on_surface")
+}
+
+func (s *DocumentSpec) OnSurfaceVariant() *Color {
+	return s.lookup("on_surface_variant")
+}
+
+func (s *DocumentSpec) OnTertiary() *Color {
+	return s.lookup("on_tertiary")
+}
+
+func (s *DocumentSpec) OnTertiaryContainer() *Color {
+	return s.lookup("on_tertiary_container")
+}
+
+func (s *DocumentSpec) OnTertiaryFixed() *Color {
+	return s.lookup("on_tertiary_fixed")
+}
+
+func (s *DocumentSpec) OnTertiaryFixedVariant() *Color {
+	return s.lookup("on_tertiary_fixed_variant")
+}
+
+func (s *DocumentSpec) Outline() *Color {
+	return s.lookup("outline")
+}
+
+func (s *DocumentSpec) OutlineVariant() *Color {
+	return s.lookup("outline_variant")
+}
+
+func (s *DocumentSpec) Primary() *Color {
+	return s.lookup("primary")
+}
+
+func (s *DocumentSpec) PrimaryContainer() *Color {
+	return s.lookup("primary_container")
+}
+
+func (s *DocumentSpec) PrimaryDim() *Color {
+	return s.lookup("primary_dim")
+}
+
+func (s *DocumentSpec) PrimaryFixed() *Color {
+	return s.lookup("primary_fixed")
+}
+
+func (s *DocumentSpec) PrimaryFixedDim() *Color {
+	return s.lookup("primary_fixed_dim")
+}
+
+func (s *DocumentSpec) PrimaryPaletteKeyColor() *Color {
+	return s.lookup("primary_palette_key_color")
+}
+
+func (s *DocumentSpec) Scrim() *Color {
+	return s.lookup("scrim")
+}
+
+func (s *DocumentSpec) Secondary() *Color {
+	return s.lookup("secondary")
+}
+
+func (s *DocumentSpec) SecondaryContainer() *Color {
+	return s.lookup("secondary_container")
+}
+
+func (s *DocumentSpec) SecondaryDim() *Color {
+	return s.lookup("secondary_dim")
+}
+
+func (s *DocumentSpec) SecondaryFixed() *Color {
+	return s.lookup("secondary_fixed")
+}
+
+func (s *DocumentSpec) SecondaryFixedDim() *Color {
+	return s.lookup("secondary_fixed_dim")
+}
+
+func (s *DocumentSpec) SecondaryPaletteKeyColor() *Color {
+	return s.lookup("secondary_palette_key_color")
+}
+
+func (s *DocumentSpec) Shadow() *Color {
+	return s.lookup("shadow")
+}
+
+func (s *DocumentSpec) Surface() *Color {
+	return s.lookup("surface")
+}
+
+func (s *DocumentSpec) SurfaceBright() *Color {
+	return s.lookup("surface_bright")
+}
+
+func (s *DocumentSpec) SurfaceContainer() *Color {
+	return s.lookup("surface_container")
+}
+
+func (s *DocumentSpec) SurfaceContainerHigh() *Color {
+	return s.lookup("surface_container_high")
+}
+
+func (s *DocumentSpec) SurfaceContainerHighest() *Color {
+	return s.lookup("surface_container_highest")
+}
+
+func (s *DocumentSpec) SurfaceContainerLow() *Color {
+	return s.lookup("surface_container_low")
+}
+
+func (s *DocumentSpec) SurfaceContainerLowest() *Color {
+	return s.lookup("surface_container_lowest")
+}
+
+func (s *DocumentSpec) SurfaceDim() *Color {
+	return s.lookup("surface_dim")
+}
+
+func (s *DocumentSpec) SurfaceTint() *Color {
+	return s.lookup("surface_tint")
+}
+
+func (s *DocumentSpec) SurfaceVariant() *Color {
+	return s.lookup("surface_variant")
+}
+
+func (s *DocumentSpec) Tertiary() *Color {
+	return s.lookup("tertiary")
+}
+
+func (s *DocumentSpec) TertiaryContainer() *Color {
+	return s.lookup("tertiary_container")
+}
+
+func (s *DocumentSpec) TertiaryDim() *Color {
+	return s.lookup("tertiary_dim")
+}
+
+func (s *DocumentSpec) TertiaryFixed() *Color {
+	return s.lookup("tertiary_fixed")
+}
+
+func (s *DocumentSpec) TertiaryFixedDim() *Color {
+	return s.lookup("tertiary_fixed_dim")
+}
+
+func (s *DocumentSpec) TertiaryPaletteKeyColor() *Color {
+	return s.lookup("tertiary_palette_key_color")
+}
@@ -0,0 +1,159 @@
+package dynamic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/contrast"
+	"github.com/Nadim147c/material/v3/palettes"
+)
+
+func TestDocumentSpecBase(t *testing.T) {
+	for _, version := range []Version{Version2021, Version2025} {
+		t.Run(version.String(), func(t *testing.T) {
+			spec, err := NewDocumentSpec(SpecDocument{Base: version})
+			if err != nil {
+				t.Fatalf("failed to create spec: %v", err)
+			}
+
+			want := testScheme(version)
+			got := testScheme(version)
+			got.MaterialColor = spec
+
+			for name, c := range got.ToColorMap() {
+				w := want.ToColorMap()[name]
+				if (c == nil) != (w == nil) {
+					t.Fatalf("%s is %v, want %v", name, c, w)
+				}
+				if c == nil {
+					continue
+				}
+				if g, w := c.GetArgb(got), w.GetArgb(want); g != w {
+					t.Errorf("%s = %s, want %s", name, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestReadSpec(t *testing.T) {
+	for _, version := range []Version{Version2021, Version2025} {
+		t.Run(version.String(), func(t *testing.T) {
+			spec, err := ReadSpec(strings.NewReader(`{
+				"base": "` + version.String() + `",
+				"roles": {
+					"primary": {
+						"palette": "primary",
+						"tone": {"light": 60, "dark": 30}
+					},
+					"primary_fixed": null
+				}
+			}`))
+			if err != nil {
+				t.Fatalf("failed to read spec: %v", err)
+			}
+			if spec.Version() != version {
+				t.Errorf("Version() = %s, want %s", spec.Version(), version)
+			}
+
+			for _, dark := range []bool{false, true} {
+				s := NewDynamicScheme(
+					color.ARGB(0xFF0044FF).ToHct(),
+					VariantTonalSpot,
+					0,
+					dark,
+					PlatformPhone,
+					version,
+				)
+				s.MaterialColor = spec
+
+				m := s.ToColorMap()
+				if m["primary_fixed"] != nil {
+					t.Errorf("removed role primary_fixed is in color map")
+				}
+
+				// on_primary of the base spec contrasts with the primary of
+				// the document, even if it's resolved before primary
+				onPrimary := m["on_primary"].GetTone(s)
+
+				want := 60.0
+				if dark {
+					want = 30
+				}
+				primary := m["primary"].GetTone(s)
+				if primary != want {
+					t.Errorf("dark=%v: primary tone = %v, want %v",
+						dark, primary, want)
+				}
+
+				if r := contrast.RatioOfTones(primary, onPrimary); r < 4.5 {
+					t.Errorf("dark=%v: on_primary contrast = %.2f, want 4.5",
+						dark, r)
+				}
+
+				// Only the roles of the base spec are replaced
+				other := FromPalette(
+					"primary",
+					func(s *Scheme) palettes.TonalPalette {
+						return s.TertiaryPalette
+					},
+					func(*Scheme) float64 { return 90 },
+				)
+				tertiary := s.TertiaryPalette.Tone(90)
+				if got := other.GetArgb(s); got != tertiary {
+					t.Errorf("dark=%v: color named primary = %s, want %s",
+						dark, got, tertiary)
+				}
+			}
+		})
+	}
+}
+
+func TestReadSpecErrors(t *testing.T) {
+	tests := map[string]string{
+		"invalid base":  `{"base": "2020"}`,
+		"unknown field": `{"base": "2025", "colors": {}}`,
+		"unknown role":  `{"base": "2025", "roles": {"link": null}}`,
+		"unknown palette": `{"base": "2025", "roles": {"primary": {
+			"palette": "accent"
+		}}}`,
+		"unknown background": `{"base": "2025", "roles": {"primary": {
+			"palette": "primary",
+			"background": "link",
+			"contrast_curve": [3, 4.5, 7, 7]
+		}}}`,
+		"removed background": `{"base": "2025", "roles": {
+			"surface": null,
+			"primary": {
+				"palette": "primary",
+				"background": "surface",
+				"contrast_curve": [3, 4.5, 7, 7]
+			}
+		}}`,
+		"background without curve": `{"base": "2025", "roles": {"primary": {
+			"palette": "primary",
+			"background": "surface"
+		}}}`,
+		"invalid curve": `{"base": "2025", "roles": {"primary": {
+			"palette": "primary",
+			"background": "surface",
+			"contrast_curve": [3, 4.5]
+		}}}`,
+		"pair without role": `{"base": "2025", "roles": {"primary": {
+			"palette": "primary",
+			"tone_delta_pair": {
+				"role_a": "secondary",
+				"role_b": "tertiary",
+				"polarity": "nearer"
+			}
+		}}}`,
+	}
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadSpec(strings.NewReader(doc)); err == nil {
+				t.Errorf("ReadSpec() succeeded")
+			}
+		})
+	}
+}
@@ -55,6 +55,14 @@ func FindDesiredChromaByTone(
 	return answer
 }
 
+// VersionedSpec is a MaterialColorSpec of a version of the spec, e.g. a
+// DocumentSpec. Schemes using the spec should have the same version.
+type VersionedSpec interface {
+	MaterialColorSpec
+	// Version returns the version of the spec.
+	Version() Version
+}
+
 //revive:disable:exported
 
 type MaterialColorSpec interface {
@@ -149,7 +157,12 @@ func DynamicColorFromPalette(args *Color) *Color {
 
 type MaterialSpec2021 struct{}
 
-var _ MaterialColorSpec = (*MaterialSpec2021)(nil)
+var _ VersionedSpec = (*MaterialSpec2021)(nil)
+
+// Version returns Version2021.
+func (m MaterialSpec2021) Version() Version {
+	return Version2021
+}
 
 // HighestSurface returns the highest surface color based on dark mode
 func (m MaterialSpec2021) HighestSurface(s *Scheme) *Color {
@@ -201,7 +201,12 @@ type MaterialSpec2025 struct {
 	MaterialSpec2021
 }
 
-var _ MaterialColorSpec = (*MaterialSpec2025)(nil)
+var _ VersionedSpec = (*MaterialSpec2025)(nil)
+
+// Version returns Version2025.
+func (m MaterialSpec2025) Version() Version {
+	return Version2025
+}
 
 func (m MaterialSpec2025) Surface() *Color {
 	color := DynamicColorFromPalette(&Color{
@@ -1,7 +1,17 @@
 package dynamic
 
 import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
 	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/internal/bin"
 	"github.com/Nadim147c/material/v3/num"
 	"github.com/Nadim147c/material/v3/palettes"
 )
@@ -28,26 +38,153 @@ type Scheme struct {
 	// -1 = minimum contrast, 0 = standard contrast, 1 = maximum contrast
 	Contrast float64 `json:"contrast"`
 	// PrimaryPalette produces colors for primary UI elements. Usually colorful.
-	PrimaryPalette palettes.TonalPalette `json:"-"`
+	PrimaryPalette palettes.TonalPalette `json:"primary_palette"`
 	// SecondaryPalette produces colors for secondary UI elements. Usually less
 	// colorful.
-	SecondaryPalette palettes.TonalPalette `json:"-"`
+	SecondaryPalette palettes.TonalPalette `json:"secondary_palette"`
 	// TertiaryPalette produces colors for tertiary UI elements. Usually a
 	// different hue from primary and colorful.
-	TertiaryPalette palettes.TonalPalette `json:"-"`
+	TertiaryPalette palettes.TonalPalette `json:"tertiary_palette"`
 	// NeutralPalette produces neutral colors for backgrounds and surfaces.
 	// Usually not colorful at all.
-	NeutralPalette palettes.TonalPalette `json:"-"`
+	NeutralPalette palettes.TonalPalette `json:"neutral_palette"`
 	// NeutralVariantPalette produces neutral variant colors for backgrounds and
 	// surfaces. Usually not colorful, but slightly more colorful than Neutral
 	// palette.
-	NeutralVariantPalette palettes.TonalPalette `json:"-"`
+	NeutralVariantPalette palettes.TonalPalette `json:"neutral_variant_palette"`
 	// ErrorPalette produces colors for error states. Usually reddish and
 	// colorful.
-	ErrorPalette palettes.TonalPalette `json:"-"`
+	ErrorPalette palettes.TonalPalette `json:"error_palette"`
 	// MaterialColor provides the material color specification implementation
-	// for the given version (2021 or 2025)
+	// for the given version (2021 or 2025). It isn't encoded and is recreated
+	// from Version when the scheme is decoded.
 	MaterialColor MaterialColorSpec `json:"-"`
+
+	// extra is the additional roles registered by Register
+	extra map[string]*Color
+	// cache memoizes resolved colors, see schemeCache
+	cache *schemeCache
+}
+
+// schemeCache memoizes the tones and colors of a scheme by role, so that
+// roles referenced by other roles, e.g. backgrounds and tone delta pairs, are
+// resolved only once. It is only used by the scheme it was created for.
+// Copies of the scheme resolve colors without the cache, so a copy can be
+// modified safely. If the scheme itself is modified, its colors are resolved
+// again. The cache is safe for concurrent use.
+type schemeCache struct {
+	owner   *Scheme
+	entries atomic.Pointer[cacheEntries]
+}
+
+// cacheEntries is the resolved colors of a scheme in a state, see roleKey.
+type cacheEntries struct {
+	state schemeState
+	spec  MaterialColorSpec
+
+	tones sync.Map // roleKey to float64
+	hcts  sync.Map // roleKey to color.Hct
+	argbs sync.Map // roleKey to color.ARGB
+
+	customs sync.Map // CustomColor to *Scheme, see CustomColor.scheme
+}
+
+// roleKey identifies a color in the scheme cache by its name and the code of
+// its functions. Specs create a new *Color on every call, and the colors of a
+// role created by the same spec method share the key. A different color with
+// the name of a role, e.g. FromPalette("primary", ...), has other functions
+// and doesn't share the entries of the role.
+type roleKey struct {
+	name         string
+	isBackground bool
+	funcs        [7]uintptr
+}
+
+// keyOf returns the roleKey of dc
+func keyOf(dc *Color) roleKey {
+	return roleKey{
+		name:         dc.Name,
+		isBackground: dc.IsBackground,
+		funcs: [7]uintptr{
+			funcCode(dc.Palette),
+			funcCode(dc.Tone),
+			funcCode(dc.ChromaMultiplier),
+			funcCode(dc.Background),
+			funcCode(dc.SecondBackground),
+			funcCode(dc.ToneDeltaPair),
+			funcCode(dc.ContrastCurve),
+		},
+	}
+}
+
+// funcCode returns the code pointer of the function f, or 0 if f is nil
+func funcCode(f any) uintptr {
+	return reflect.ValueOf(f).Pointer()
+}
+
+// schemeState is the values of a scheme that colors are resolved with, except
+// the spec which may not be comparable.
+type schemeState struct {
+	source   color.Hct
+	variant  Variant
+	dark     bool
+	platform Platform
+	version  Version
+	contrast float64
+	palettes [6]palettes.TonalPalette
+}
+
+// state returns the current state of d
+func (d *Scheme) state() schemeState {
+	return schemeState{
+		source:   d.SourceColorHct,
+		variant:  d.Variant,
+		dark:     d.Dark,
+		platform: d.Platform,
+		version:  d.Version,
+		contrast: d.Contrast,
+		palettes: [6]palettes.TonalPalette{
+			d.PrimaryPalette,
+			d.SecondaryPalette,
+			d.TertiaryPalette,
+			d.NeutralPalette,
+			d.NeutralVariantPalette,
+			d.ErrorPalette,
+		},
+	}
+}
+
+// resolved returns the cache entries of the current state of d. The entries
+// are replaced if d is modified after they were created. Returns nil if d is
+// not the owner of its cache or its spec isn't comparable.
+func (d *Scheme) resolved() *cacheEntries {
+	c := d.cache
+	if c == nil || c.owner != d {
+		return nil
+	}
+
+	state := d.state()
+	e := c.entries.Load()
+	if e != nil && e.state == state && sameSpec(e.spec, d.MaterialColor) {
+		return e
+	}
+	if !sameSpec(d.MaterialColor, d.MaterialColor) {
+		return nil
+	}
+	n := &cacheEntries{state: state, spec: d.MaterialColor}
+	c.entries.CompareAndSwap(e, n)
+	return n
+}
+
+// memoize returns the value of key in m. The value is computed and stored if
+// m doesn't have it.
+func memoize[T any](m *sync.Map, key any, compute func() T) T {
+	if v, ok := m.Load(key); ok {
+		return v.(T)
+	}
+	v := compute()
+	m.Store(key, v)
+	return v
 }
 
 // NewDynamicScheme creates a dynamic color scheme from a source color and theme
@@ -151,7 +288,7 @@ func NewDynamicScheme(
 		errorPalette = palettes.FromHueAndChroma(25.0, 84.0)
 	}
 
-	return &Scheme{
+	scheme := &Scheme{
 		SourceColorHct:        sourceColorHct,
 		Variant:               variant,
 		Dark:                  dark,
@@ -166,6 +303,158 @@ func NewDynamicScheme(
 		ErrorPalette:          *errorPalette,
 		MaterialColor:         colorSpec,
 	}
+	scheme.cache = &schemeCache{owner: scheme}
+	return scheme
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. MaterialColor is
+// recreated from Version and missing palettes are generated from the source
+// color, so schemes encoded without palettes can be decoded too.
+func (d *Scheme) UnmarshalJSON(data []byte) error {
+	var v struct {
+		SourceColorHct        color.Hct              `json:"source_color_hct"`
+		Variant               Variant                `json:"variant"`
+		Dark                  bool                   `json:"dark"`
+		Platform              Platform               `json:"platform"`
+		Version               Version                `json:"version"`
+		Contrast              float64                `json:"contrast"`
+		PrimaryPalette        *palettes.TonalPalette `json:"primary_palette"`
+		SecondaryPalette      *palettes.TonalPalette `json:"secondary_palette"`
+		TertiaryPalette       *palettes.TonalPalette `json:"tertiary_palette"`
+		NeutralPalette        *palettes.TonalPalette `json:"neutral_palette"`
+		NeutralVariantPalette *palettes.TonalPalette `json:"neutral_variant_palette"`
+		ErrorPalette          *palettes.TonalPalette `json:"error_palette"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	d.set(NewDynamicScheme(
+		v.SourceColorHct,
+		v.Variant,
+		v.Contrast,
+		v.Dark,
+		v.Platform,
+		v.Version,
+		v.PrimaryPalette,
+		v.SecondaryPalette,
+		v.TertiaryPalette,
+		v.NeutralPalette,
+		v.NeutralVariantPalette,
+		v.ErrorPalette,
+	))
+	return nil
+}
+
+// schemeBinaryVersion is the version of the binary encoding of Scheme
+const schemeBinaryVersion = 1
+
+// schemeBinarySize is the size of a binary encoded Scheme: the encoding
+// version, the source color, variant, platform and version as uint16, dark,
+// contrast and six palettes
+const schemeBinarySize = 1 + 3*8 + 3*2 + 1 + 8 + 6*palettes.BinarySize
+
+var errSchemeBinary = errors.New("invalid binary scheme")
+
+var errUnnamedColor = errors.New("color has no name")
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (d Scheme) MarshalBinary() ([]byte, error) {
+	return d.AppendBinary(make([]byte, 0, schemeBinarySize))
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface. Floats and
+// enums are appended as big endian float64 and uint16, and palettes as
+// encoded by palettes.TonalPalette.AppendBinary.
+func (d Scheme) AppendBinary(b []byte) ([]byte, error) {
+	b = append(b, schemeBinaryVersion)
+	b = bin.AppendFloat64(b, d.SourceColorHct.Hue)
+	b = bin.AppendFloat64(b, d.SourceColorHct.Chroma)
+	b = bin.AppendFloat64(b, d.SourceColorHct.Tone)
+
+	var dark byte
+	if d.Dark {
+		dark = 1
+	}
+	b = binary.BigEndian.AppendUint16(b, uint16(d.Variant))
+	b = binary.BigEndian.AppendUint16(b, uint16(d.Platform))
+	b = binary.BigEndian.AppendUint16(b, uint16(d.Version))
+	b = append(b, dark)
+	b = bin.AppendFloat64(b, d.Contrast)
+
+	for _, p := range d.tonalPalettes() {
+		var err error
+		if b, err = p.AppendBinary(b); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// MaterialColor is recreated from Version.
+func (d *Scheme) UnmarshalBinary(data []byte) error {
+	if len(data) != schemeBinarySize || data[0] != schemeBinaryVersion {
+		return errSchemeBinary
+	}
+	data = data[1:]
+
+	float := func() float64 {
+		f := bin.Float64(data)
+		data = data[8:]
+		return f
+	}
+
+	source := color.Hct{Hue: float(), Chroma: float(), Tone: float()}
+	variant := Variant(binary.BigEndian.Uint16(data))
+	platform := Platform(binary.BigEndian.Uint16(data[2:]))
+	version := Version(binary.BigEndian.Uint16(data[4:]))
+	dark := data[6] == 1
+	data = data[7:]
+	contrast := float()
+
+	if !variant.IsValid() {
+		return fmt.Errorf("%w: %w", errSchemeBinary, ErrInvalidVariant)
+	}
+	if !platform.IsValid() {
+		return fmt.Errorf("%w: %w", errSchemeBinary, ErrInvalidPlatform)
+	}
+	if !version.IsValid() {
+		return fmt.Errorf("%w: %w", errSchemeBinary, ErrInvalidVersion)
+	}
+
+	var tps [6]*palettes.TonalPalette
+	for i := range tps {
+		tps[i] = &palettes.TonalPalette{}
+		err := tps[i].UnmarshalBinary(data[:palettes.BinarySize])
+		if err != nil {
+			return err
+		}
+		data = data[palettes.BinarySize:]
+	}
+
+	d.set(NewDynamicScheme(
+		source, variant, contrast, dark, platform, version, tps[:]...,
+	))
+	return nil
+}
+
+// set sets d to s. The cache of s is replaced, because it belongs to s.
+func (d *Scheme) set(s *Scheme) {
+	*d = *s
+	d.cache = &schemeCache{owner: d}
+}
+
+// tonalPalettes returns the palettes of d in the order of NewDynamicScheme
+func (d *Scheme) tonalPalettes() []*palettes.TonalPalette {
+	return []*palettes.TonalPalette{
+		&d.PrimaryPalette,
+		&d.SecondaryPalette,
+		&d.TertiaryPalette,
+		&d.NeutralPalette,
+		&d.NeutralVariantPalette,
+		&d.ErrorPalette,
+	}
 }
 
 // GetPiecewiseHue returns a new hue based on a piece wise function and the
@@ -207,66 +496,123 @@ func (d Scheme) SourceColorARGB() color.ARGB {
 	return d.SourceColorHct.ToARGB()
 }
 
-// ToColorMap creates a map of color name as key and *Color as value.
+// ToColorMap creates a map of color name as key and *Color as value, including
+// the colors registered by Register.
 func (d Scheme) ToColorMap() map[string]*Color {
+	m := d.newColorMap()
+	maps.Copy(m, d.extra)
+	return m
+}
+
+// Register registers colors as additional roles of the scheme, e.g. success
+// or warning roles of a design system. The colors are included in ToColorMap
+// and resolved like the built-in roles by the color calculation delegate of
+// the scheme version, so they can use built-in roles as background or in tone
+// delta pairs. Returns an error if a color has no name, palette or tone, or
+// is named like a built-in or registered role. Registered colors are not
+// encoded.
+//
+// Register resets the cache of the scheme, so it must not be called
+// concurrently with other methods of the scheme.
+func (d *Scheme) Register(colors ...*Color) error {
+	builtin := d.newColorMap()
+	extra := maps.Clone(d.extra)
+	if extra == nil {
+		extra = make(map[string]*Color, len(colors))
+	}
+	for _, c := range colors {
+		switch {
+		case c == nil || c.Name == "":
+			return errUnnamedColor
+		case c.Palette == nil || c.Tone == nil:
+			return fmt.Errorf("color %q has no palette or tone", c.Name)
+		}
+		if _, ok := builtin[c.Name]; ok {
+			return fmt.Errorf("color %q is a built-in role", c.Name)
+		}
+		if _, ok := extra[c.Name]; ok {
+			return fmt.Errorf("color %q is already registered", c.Name)
+		}
+		extra[c.Name] = c
+	}
+
+	d.extra = extra
+	d.cache = &schemeCache{owner: d}
+	return nil
+}
+
+// sameSpec reports whether a and b are the same comparable spec
+func sameSpec(a, b MaterialColorSpec) bool {
+	t := reflect.TypeOf(a)
+	return t != nil && t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+// newColorMap creates a map of color name as key and *Color as value.
+func (d Scheme) newColorMap() map[string]*Color {
+	return specColorMap(d.MaterialColor)
+}
+
+// specColorMap creates a map of the role name as key and the role of spec as
+// value.
+func specColorMap(spec MaterialColorSpec) map[string]*Color {
 	return map[string]*Color{
-		"primary_palette_key_color":         d.MaterialColor.PrimaryPaletteKeyColor(),
-		"secondary_palette_key_color":       d.MaterialColor.SecondaryPaletteKeyColor(),
-		"tertiary_palette_key_color":        d.MaterialColor.TertiaryPaletteKeyColor(),
-		"neutral_palette_key_color":         d.MaterialColor.NeutralPaletteKeyColor(),
-		"neutral_variant_palette_key_color": d.MaterialColor.NeutralVariantPaletteKeyColor(),
-		"background":                        d.MaterialColor.Background(),
-		"on_background":                     d.MaterialColor.OnBackground(),
-		"surface":                           d.MaterialColor.Surface(),
-		"surface_dim":                       d.MaterialColor.SurfaceDim(),
-		"surface_bright":                    d.MaterialColor.SurfaceBright(),
-		"surface_container_lowest":          d.MaterialColor.SurfaceContainerLowest(),
-		"surface_container_low":             d.MaterialColor.SurfaceContainerLow(),
-		"surface_container":                 d.MaterialColor.SurfaceContainer(),
-		"surface_container_high":            d.MaterialColor.SurfaceContainerHigh(),
-		"surface_container_highest":         d.MaterialColor.SurfaceContainerHighest(),
-		"on_surface":                        d.MaterialColor.OnSurface(),
-		"surface_variant":                   d.MaterialColor.SurfaceVariant(),
-		"on_surface_variant":                d.MaterialColor.OnSurfaceVariant(),
-		"inverse_surface":                   d.MaterialColor.InverseSurface(),
-		"inverse_on_surface":                d.MaterialColor.InverseOnSurface(),
-		"outline":                           d.MaterialColor.Outline(),
-		"outline_variant":                   d.MaterialColor.OutlineVariant(),
-		"shadow":                            d.MaterialColor.Shadow(),
-		"scrim":                             d.MaterialColor.Scrim(),
-		"surface_tint":                      d.MaterialColor.SurfaceTint(),
-		"primary":                           d.MaterialColor.Primary(),
-		"on_primary":                        d.MaterialColor.OnPrimary(),
-		"primary_container":                 d.MaterialColor.PrimaryContainer(),
-		"primary_dim":                       d.MaterialColor.PrimaryDim(),
-		"on_primary_container":              d.MaterialColor.OnPrimaryContainer(),
-		"inverse_primary":                   d.MaterialColor.InversePrimary(),
-		"secondary":                         d.MaterialColor.Secondary(),
-		"on_secondary":                      d.MaterialColor.OnSecondary(),
-		"secondary_container":               d.MaterialColor.SecondaryContainer(),
-		"secondary_dim":                     d.MaterialColor.SecondaryDim(),
-		"on_secondary_container":            d.MaterialColor.OnSecondaryContainer(),
-		"tertiary":                          d.MaterialColor.Tertiary(),
-		"on_tertiary":                       d.MaterialColor.OnTertiary(),
-		"tertiary_container":                d.MaterialColor.TertiaryContainer(),
-		"tertiary_dim":                      d.MaterialColor.TertiaryDim(),
-		"on_tertiary_container":             d.MaterialColor.OnTertiaryContainer(),
-		"error":                             d.MaterialColor.Error(),
-		"on_error":                          d.MaterialColor.OnError(),
-		"error_container":                   d.MaterialColor.ErrorContainer(),
-		"error_dim":                         d.MaterialColor.ErrorDim(),
-		"on_error_container":                d.MaterialColor.OnErrorContainer(),
-		"primary_fixed":                     d.MaterialColor.PrimaryFixed(),
-		"primary_fixed_dim":                 d.MaterialColor.PrimaryFixedDim(),
-		"on_primary_fixed":                  d.MaterialColor.OnPrimaryFixed(),
-		"on_primary_fixed_variant":          d.MaterialColor.OnPrimaryFixedVariant(),
-		"secondary_fixed":                   d.MaterialColor.SecondaryFixed(),
-		"secondary_fixed_dim":               d.MaterialColor.SecondaryFixedDim(),
-		"on_secondary_fixed":                d.MaterialColor.OnSecondaryFixed(),
-		"on_secondary_fixed_variant":        d.MaterialColor.OnSecondaryFixedVariant(),
-		"tertiary_fixed":                    d.MaterialColor.TertiaryFixed(),
-		"tertiary_fixed_dim":                d.MaterialColor.TertiaryFixedDim(),
-		"on_tertiary_fixed":                 d.MaterialColor.OnTertiaryFixed(),
-		"on_tertiary_fixed_variant":         d.MaterialColor.OnTertiaryFixedVariant(),
+		"primary_palette_key_color":         spec.PrimaryPaletteKeyColor(),
+		"secondary_palette_key_color":       spec.SecondaryPaletteKeyColor(),
+		"tertiary_palette_key_color":        spec.TertiaryPaletteKeyColor(),
+		"neutral_palette_key_color":         spec.NeutralPaletteKeyColor(),
+		"neutral_variant_palette_key_color": spec.NeutralVariantPaletteKeyColor(),
+		"background":                        spec.Background(),
+		"on_background":                     spec.OnBackground(),
+		"surface":                           spec.Surface(),
+		"surface_dim":                       spec.SurfaceDim(),
+		"surface_bright":                    spec.SurfaceBright(),
+		"surface_container_lowest":          spec.SurfaceContainerLowest(),
+		"surface_container_low":             spec.SurfaceContainerLow(),
+		"surface_container":                 spec.SurfaceContainer(),
+		"surface_container_high":            spec.SurfaceContainerHigh(),
+		"surface_container_highest":         spec.SurfaceContainerHighest(),
+		"on_surface":                        spec.OnSurface(),
+		"surface_variant":                   spec.SurfaceVariant(),
+		"on_surface_variant":                spec.OnSurfaceVariant(),
+		"inverse_surface":                   spec.InverseSurface(),
+		"inverse_on_surface":                spec.InverseOnSurface(),
+		"outline":                           spec.Outline(),
+		"outline_variant":                   spec.OutlineVariant(),
+		"shadow":                            spec.Shadow(),
+		"scrim":                             spec.Scrim(),
+		"surface_tint":                      spec.SurfaceTint(),
+		"primary":                           spec.Primary(),
+		"on_primary":                        spec.OnPrimary(),
+		"primary_container":                 spec.PrimaryContainer(),
+		"primary_dim":                       spec.PrimaryDim(),
+		"on_primary_container":              spec.OnPrimaryContainer(),
+		"inverse_primary":                   spec.InversePrimary(),
+		"secondary":                         spec.Secondary(),
+		"on_secondary":                      spec.OnSecondary(),
+		"secondary_container":               spec.SecondaryContainer(),
+		"secondary_dim":                     spec.SecondaryDim(),
+		"on_secondary_container":            spec.OnSecondaryContainer(),
+		"tertiary":                          spec.Tertiary(),
+		"on_tertiary":                       spec.OnTertiary(),
+		"tertiary_container":                spec.TertiaryContainer(),
+		"tertiary_dim":                      spec.TertiaryDim(),
+		"on_tertiary_container":             spec.OnTertiaryContainer(),
+		"error":                             spec.Error(),
+		"on_error":                          spec.OnError(),
+		"error_container":                   spec.ErrorContainer(),
+		"error_dim":                         spec.ErrorDim(),
+		"on_error_container":                spec.OnErrorContainer(),
+		"primary_fixed":                     spec.PrimaryFixed(),
+		"primary_fixed_dim":                 spec.PrimaryFixedDim(),
+		"on_primary_fixed":                  spec.OnPrimaryFixed(),
+		"on_primary_fixed_variant":          spec.OnPrimaryFixedVariant(),
+		"secondary_fixed":                   spec.SecondaryFixed(),
+		"secondary_fixed_dim":               spec.SecondaryFixedDim(),
+		"on_secondary_fixed":                spec.OnSecondaryFixed(),
+		"on_secondary_fixed_variant":        spec.OnSecondaryFixedVariant(),
+		"tertiary_fixed":                    spec.TertiaryFixed(),
+		"tertiary_fixed_dim":                spec.TertiaryFixedDim(),
+		"on_tertiary_fixed":                 spec.OnTertiaryFixed(),
+		"on_tertiary_fixed_variant":         spec.OnTertiaryFixedVariant(),
 	}
 }
@@ -0,0 +1,89 @@
+package dynamic
+
+import (
+	"testing"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+func testScheme(version Version) *Scheme {
+	return NewDynamicScheme(
+		color.ARGB(0xFF0044FF).ToHct(),
+		VariantTonalSpot,
+		0,
+		false,
+		PlatformPhone,
+		version,
+	)
+}
+
+func BenchmarkNewDynamicScheme(b *testing.B) {
+	for _, version := range []Version{Version2021, Version2025} {
+		b.Run(version.String(), func(b *testing.B) {
+			for b.Loop() {
+				testScheme(version)
+			}
+		})
+	}
+}
+
+// BenchmarkResolveScheme resolves every color of a new scheme. Creating the
+// scheme is not measured.
+func BenchmarkResolveScheme(b *testing.B) {
+	for _, version := range []Version{Version2021, Version2025} {
+		b.Run(version.String(), func(b *testing.B) {
+			for b.Loop() {
+				b.StopTimer()
+				s := testScheme(version)
+				b.StartTimer()
+				for _, c := range s.ToColorMap() {
+					if c != nil {
+						c.GetArgb(s)
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkResolveSchemeUncached resolves every color of a new scheme without
+// the scheme cache, as a baseline for BenchmarkResolveScheme. A copy of a
+// scheme doesn't own the cache of the scheme, so it isn't cached.
+func BenchmarkResolveSchemeUncached(b *testing.B) {
+	for _, version := range []Version{Version2021, Version2025} {
+		b.Run(version.String(), func(b *testing.B) {
+			for b.Loop() {
+				b.StopTimer()
+				s := *testScheme(version)
+				b.StartTimer()
+				for _, c := range s.ToColorMap() {
+					if c != nil {
+						c.GetArgb(&s)
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkResolveColor resolves a single color of a scheme which is already
+// resolved.
+func BenchmarkResolveColor(b *testing.B) {
+	for _, version := range []Version{Version2021, Version2025} {
+		b.Run(version.String(), func(b *testing.B) {
+			s := testScheme(version)
+			primary := s.MaterialColor.Primary()
+			primary.GetArgb(s)
+			for b.Loop() {
+				primary.GetArgb(s)
+			}
+		})
+	}
+}
+
+func BenchmarkToColorMap(b *testing.B) {
+	s := testScheme(Version2025)
+	for b.Loop() {
+		s.ToColorMap()
+	}
+}
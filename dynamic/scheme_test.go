@@ -0,0 +1,268 @@
+package dynamic
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/contrast"
+	"github.com/Nadim147c/material/v3/palettes"
+)
+
+func TestSchemeCache(t *testing.T) {
+	for _, version := range []Version{Version2021, Version2025} {
+		t.Run(version.String(), func(t *testing.T) {
+			s := testScheme(version)
+			uncached := *s
+
+			colors := s.ToColorMap()
+			for name, c := range colors {
+				if c == nil {
+					continue
+				}
+				want := c.GetArgb(&uncached)
+				for range 2 {
+					if got := c.GetArgb(s); got != want {
+						t.Errorf("%s = %s, want %s", name, got, want)
+					}
+				}
+			}
+
+			dark := *s
+			dark.Dark = true
+			c := s.MaterialColor.Surface()
+			if c.GetArgb(&dark) == c.GetArgb(s) {
+				t.Errorf("modified copy of scheme uses the cache")
+			}
+
+			colors["primary"] = nil
+			if s.ToColorMap()["primary"] == nil {
+				t.Errorf("ToColorMap() returned the modified map")
+			}
+		})
+	}
+}
+
+func TestSchemeCacheMutation(t *testing.T) {
+	tests := map[string]func(s *Scheme){
+		"dark":     func(s *Scheme) { s.Dark = true },
+		"contrast": func(s *Scheme) { s.Contrast = 1 },
+		"variant":  func(s *Scheme) { s.Variant = VariantMonochrome },
+		"palette": func(s *Scheme) {
+			s.PrimaryPalette = *palettes.FromHueAndChroma(120, 48)
+		},
+		"spec": func(s *Scheme) {
+			s.Version = Version2021
+			s.MaterialColor = MaterialSpec2021{}
+		},
+	}
+
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := testScheme(Version2025)
+			for _, c := range s.ToColorMap() {
+				if c != nil {
+					c.GetArgb(s)
+				}
+			}
+
+			modify(s)
+			uncached := *s
+			for role, c := range s.ToColorMap() {
+				if c == nil {
+					continue
+				}
+				if got, want := c.GetArgb(s), c.GetArgb(&uncached); got != want {
+					t.Errorf("%s = %s, want %s", role, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemeCacheColors(t *testing.T) {
+	s := testScheme(Version2025)
+	s.MaterialColor.Primary().GetArgb(s)
+
+	primary := FromPalette(
+		"primary",
+		func(s *Scheme) palettes.TonalPalette { return s.TertiaryPalette },
+		func(*Scheme) float64 { return 90 },
+	)
+	if got, want := primary.GetArgb(s), s.TertiaryPalette.Tone(90); got != want {
+		t.Errorf("color named primary = %s, want %s", got, want)
+	}
+}
+
+func TestSchemeRolesMutation(t *testing.T) {
+	want := testScheme(Version2025).MaterialColor.Surface()
+	for _, s := range []*Scheme{
+		testScheme(Version2021),
+		testScheme(Version2025),
+	} {
+		s.ToColorMap()["surface"].Tone = func(*Scheme) float64 { return 0 }
+		s.MaterialColor.Surface().Tone = func(*Scheme) float64 { return 0 }
+	}
+
+	s := testScheme(Version2025)
+	if got := s.MaterialColor.Surface().GetArgb(s); got != want.GetArgb(s) {
+		t.Errorf("surface = %s, want %s", got, want.GetArgb(s))
+	}
+}
+
+func TestSchemeEncoding(t *testing.T) {
+	for _, version := range []Version{Version2021, Version2025} {
+		t.Run(version.String(), func(t *testing.T) {
+			want := NewDynamicScheme(
+				color.ARGB(0xFF0044FF).ToHct(),
+				VariantExpressive,
+				0.5,
+				true,
+				PlatformWatch,
+				version,
+				palettes.FromHueAndChroma(120, 48),
+			)
+
+			data, err := json.Marshal(want)
+			if err != nil {
+				t.Fatalf("failed to marshal scheme: %v", err)
+			}
+			var fromJSON Scheme
+			if err := json.Unmarshal(data, &fromJSON); err != nil {
+				t.Fatalf("failed to unmarshal scheme: %v", err)
+			}
+
+			data, err = want.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal scheme: %v", err)
+			}
+			var fromBinary Scheme
+			if err := fromBinary.UnmarshalBinary(data); err != nil {
+				t.Fatalf("failed to unmarshal scheme: %v", err)
+			}
+
+			for name, got := range map[string]*Scheme{
+				"json":   &fromJSON,
+				"binary": &fromBinary,
+			} {
+				if got.MaterialColor == nil {
+					t.Fatalf("%s: MaterialColor is nil", name)
+				}
+				if got.resolved() == nil {
+					t.Errorf("%s: decoded scheme has no cache", name)
+				}
+				if got.PrimaryPalette.Hue != want.PrimaryPalette.Hue {
+					t.Errorf("%s: primary palette hue = %v, want %v", name,
+						got.PrimaryPalette.Hue, want.PrimaryPalette.Hue)
+				}
+				for role, c := range want.ToColorMap() {
+					if c == nil {
+						continue
+					}
+					if g, w := c.GetArgb(got), c.GetArgb(want); g != w {
+						t.Errorf("%s: %s = %s, want %s", name, role, g, w)
+					}
+				}
+			}
+
+			if err := fromBinary.UnmarshalBinary(data[1:]); err == nil {
+				t.Errorf("UnmarshalBinary() of truncated data succeeded")
+			}
+		})
+	}
+}
+
+func TestCustomColor(t *testing.T) {
+	for _, version := range []Version{Version2021, Version2025} {
+		for _, platform := range []Platform{PlatformPhone, PlatformWatch} {
+			for _, dark := range []bool{false, true} {
+				for _, contrast := range []float64{-1, 0, 0.5, 1} {
+					s := NewDynamicScheme(
+						color.ARGB(0xFF0044FF).ToHct(),
+						VariantTonalSpot,
+						contrast,
+						dark,
+						platform,
+						version,
+					)
+
+					custom := NewCustomColor("brand", s.TertiaryPalette)
+					roles := custom.Roles(s.MaterialColor)
+					for name, c := range s.ToColorMap() {
+						if !strings.Contains(name, "tertiary") ||
+							name == "tertiary_palette_key_color" {
+							continue
+						}
+
+						role := strings.Replace(name, "tertiary", "brand", 1)
+						if (c == nil) != (roles[role] == nil) {
+							t.Fatalf("%s: role %s is %v, want %v",
+								version, role, roles[role], c)
+						}
+						if c == nil {
+							continue
+						}
+						got, want := roles[role].GetArgb(s), c.GetArgb(s)
+						if got != want {
+							t.Errorf("%s %s dark=%v contrast=%v: %s = %s, "+
+								"want %s", version, platform, dark, contrast,
+								role, got, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	for _, version := range []Version{Version2021, Version2025} {
+		t.Run(version.String(), func(t *testing.T) {
+			s := testScheme(version)
+			success := &Color{
+				Name: "success",
+				Palette: func(*Scheme) palettes.TonalPalette {
+					return *palettes.FromHueAndChroma(145, 48)
+				},
+				Tone: func(s *Scheme) float64 {
+					if s.Dark {
+						return 80
+					}
+					return 40
+				},
+				IsBackground: true,
+				Background: func(s *Scheme) *Color {
+					return s.MaterialColor.HighestSurface(s)
+				},
+				ContrastCurve: func(*Scheme) *ContrastCurve {
+					return NewContrastCurve(3, 4.5, 7, 7)
+				},
+			}
+			if err := s.Register(success); err != nil {
+				t.Fatalf("failed to register color: %v", err)
+			}
+
+			if s.ToColorMap()["success"] != success {
+				t.Fatalf("ToColorMap() doesn't contain registered color")
+			}
+			bg := s.MaterialColor.HighestSurface(s).GetTone(s)
+			tone := success.GetTone(s)
+			if r := contrast.RatioOfTones(bg, tone); r < 4.5 {
+				t.Errorf("contrast of success = %.2f, want 4.5", r)
+			}
+
+			invalid := []*Color{
+				nil,
+				{Name: "x"},
+				s.MaterialColor.Primary(),
+				FromPalette("success", success.Palette, success.Tone),
+			}
+			for _, c := range invalid {
+				if err := s.Register(c); err == nil {
+					t.Errorf("Register(%v) succeeded", c)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/Nadim147c/material/v3"
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/internal/strcase"
+)
+
+// composeRoles is the list of roles accepted by lightColorScheme and
+// darkColorScheme of Jetpack Compose Material 3, in snake case.
+var composeRoles = []string{
+	"primary",
+	"on_primary",
+	"primary_container",
+	"on_primary_container",
+	"inverse_primary",
+	"secondary",
+	"on_secondary",
+	"secondary_container",
+	"on_secondary_container",
+	"tertiary",
+	"on_tertiary",
+	"tertiary_container",
+	"on_tertiary_container",
+	"background",
+	"on_background",
+	"surface",
+	"on_surface",
+	"surface_variant",
+	"on_surface_variant",
+	"surface_tint",
+	"inverse_surface",
+	"inverse_on_surface",
+	"error",
+	"on_error",
+	"error_container",
+	"on_error_container",
+	"outline",
+	"outline_variant",
+	"scrim",
+	"surface_bright",
+	"surface_container",
+	"surface_container_high",
+	"surface_container_highest",
+	"surface_container_low",
+	"surface_container_lowest",
+	"surface_dim",
+}
+
+// AndroidXML writes colors as an Android color resources file. Light colors
+// belong in res/values/colors.xml and dark colors in
+// res/values-night/colors.xml.
+//
+// Resources are named md_theme_<role>, e.g. md_theme_on_primary. Resources of
+// colors with reduced, medium and high contrast level are suffixed with
+// _reduced_contrast, _medium_contrast and _high_contrast. All colors must have
+// the same mode and different contrast levels, otherwise an error is returned.
+func AndroidXML(w io.Writer, colors ...*material.Colors) error {
+	if _, err := schemeNames(colors); err != nil {
+		return err
+	}
+	for _, c := range colors {
+		if c.Scheme.Dark != colors[0].Scheme.Dark {
+			return errMixedModes
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n")
+	bw.WriteString("<resources>\n")
+
+	for _, c := range colors {
+		suffix := strcase.Snake(contrastName(c.Scheme.Contrast))
+		if suffix != "" {
+			suffix = "_" + suffix
+		}
+
+		m := c.Map()
+		for _, role := range sortedKeys(m) {
+			fmt.Fprintf(bw, "    <color name=\"md_theme_%s%s\">%s</color>\n",
+				identifier(role), suffix, m[role].HexARGB())
+		}
+	}
+
+	bw.WriteString("</resources>\n")
+	return bw.Flush()
+}
+
+// Compose writes colors as a Kotlin file of Jetpack Compose color schemes in
+// the given package.
+//
+// For every colors a lightColorScheme or darkColorScheme named after its mode
+// and contrast level is generated, e.g. lightScheme, darkMediumContrastScheme
+// or lightHighContrastScheme. Roles which are not part of Compose ColorScheme
+// (fixed, dim and custom colors) are generated as ExtendedColorScheme values,
+// e.g. extendedLight or extendedDarkHighContrast. Returns an error if two
+// colors have the same mode and contrast level.
+func Compose(w io.Writer, pkg string, colors ...*material.Colors) error {
+	type scheme struct {
+		name   string
+		dark   bool
+		roles  map[string]color.ARGB
+		custom map[string]material.CustomColor
+	}
+
+	names, err := schemeNames(colors)
+	if err != nil {
+		return err
+	}
+
+	schemes := make([]scheme, 0, len(colors))
+	extended := map[string]bool{}
+	custom := map[string]bool{}
+	for i, c := range colors {
+		s := scheme{
+			name:   names[i],
+			dark:   c.Scheme.Dark,
+			roles:  schemeRoles(c),
+			custom: map[string]material.CustomColor{},
+		}
+		for role := range s.roles {
+			if !slices.Contains(composeRoles, role) {
+				extended[role] = true
+			}
+		}
+		for n, cc := range c.CustomColors {
+			s.custom[identifier(n)] = cc
+			custom[identifier(n)] = true
+		}
+		schemes = append(schemes, s)
+	}
+
+	extendedRoles := sortedKeys(extended)
+	customNames := sortedKeys(custom)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "package %s\n\n", pkg)
+	bw.WriteString("import androidx.compose.material3.darkColorScheme\n")
+	bw.WriteString("import androidx.compose.material3.lightColorScheme\n")
+	bw.WriteString("import androidx.compose.runtime.Immutable\n")
+	bw.WriteString("import androidx.compose.ui.graphics.Color\n")
+
+	for _, s := range schemes {
+		suffix := strcase.Capitalize(s.name)
+		bw.WriteString("\n")
+		for _, role := range sortedKeys(s.roles) {
+			fmt.Fprintf(bw, "val %s%s = %s\n",
+				strcase.Camel(role), suffix, kotlinColor(s.roles[role]))
+		}
+		for _, n := range sortedKeys(s.custom) {
+			for _, role := range customRoles {
+				if argb := role.color(s.custom[n]); argb != 0 {
+					fmt.Fprintf(bw, "val %s%s = %s\n",
+						strcase.Camel(fmt.Sprintf(role.format, n)), suffix,
+						kotlinColor(argb))
+				}
+			}
+		}
+	}
+
+	for _, s := range schemes {
+		suffix := strcase.Capitalize(s.name)
+		constructor := "lightColorScheme"
+		if s.dark {
+			constructor = "darkColorScheme"
+		}
+
+		fmt.Fprintf(bw, "\nval %sScheme = %s(\n", s.name, constructor)
+		for _, role := range composeRoles {
+			if _, ok := s.roles[role]; ok {
+				fmt.Fprintf(bw, "    %s = %s%s,\n",
+					strcase.Camel(role), strcase.Camel(role), suffix)
+			}
+		}
+		bw.WriteString(")\n")
+	}
+
+	bw.WriteString("\n@Immutable\n")
+	bw.WriteString("data class ColorFamily(\n")
+	for _, role := range customRoles {
+		fmt.Fprintf(bw, "    val %s: Color,\n",
+			strcase.Camel(fmt.Sprintf(role.format, "color")))
+	}
+	bw.WriteString(")\n")
+
+	bw.WriteString("\n@Immutable\n")
+	bw.WriteString("data class ExtendedColorScheme(\n")
+	for _, role := range extendedRoles {
+		fmt.Fprintf(bw, "    val %s: Color,\n", strcase.Camel(role))
+	}
+	for _, n := range customNames {
+		fmt.Fprintf(bw, "    val %s: ColorFamily,\n", strcase.Camel(n))
+	}
+	bw.WriteString(")\n")
+
+	for _, s := range schemes {
+		suffix := strcase.Capitalize(s.name)
+		fmt.Fprintf(bw, "\nval extended%s = ExtendedColorScheme(\n", suffix)
+		for _, role := range extendedRoles {
+			value := "Color.Unspecified"
+			if _, ok := s.roles[role]; ok {
+				value = strcase.Camel(role) + suffix
+			}
+			fmt.Fprintf(bw, "    %s = %s,\n", strcase.Camel(role), value)
+		}
+		for _, n := range customNames {
+			cc, ok := s.custom[n]
+			fmt.Fprintf(bw, "    %s = ColorFamily(\n", strcase.Camel(n))
+			for _, role := range customRoles {
+				value := "Color.Unspecified"
+				if ok && role.color(cc) != 0 {
+					value = strcase.Camel(fmt.Sprintf(role.format, n)) + suffix
+				}
+				fmt.Fprintf(bw, "        %s = %s,\n",
+					strcase.Camel(fmt.Sprintf(role.format, "color")), value)
+			}
+			bw.WriteString("    ),\n")
+		}
+		bw.WriteString(")\n")
+	}
+
+	return bw.Flush()
+}
+
+// kotlinColor returns c as a Compose Color constructor call.
+func kotlinColor(c color.ARGB) string {
+	return fmt.Sprintf("Color(0x%08X)", uint32(c))
+}
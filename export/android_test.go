@@ -0,0 +1,156 @@
+package export
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/Nadim147c/material/v3"
+	"github.com/Nadim147c/material/v3/dynamic"
+)
+
+func TestAndroidXML(t *testing.T) {
+	light := generate(t)
+	lightHigh := generate(t, material.WithContrast(1))
+
+	var sb strings.Builder
+	if err := AndroidXML(&sb, light, lightHigh); err != nil {
+		t.Fatalf("AndroidXML() failed: %v", err)
+	}
+
+	var resources struct {
+		Colors []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"color"`
+	}
+	if err := xml.Unmarshal([]byte(sb.String()), &resources); err != nil {
+		t.Fatalf("AndroidXML() output is not valid xml: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, c := range resources.Colors {
+		got[c.Name] = c.Value
+	}
+
+	success := light.CustomColors["success"]
+	wants := map[string]string{
+		"md_theme_primary":            light.Primary.HexARGB(),
+		"md_theme_primary_dim":        light.PrimaryDim.HexARGB(),
+		"md_theme_success":            success.Color.HexARGB(),
+		"md_theme_on_surface_variant": light.OnSurfaceVariant.HexARGB(),
+		"md_theme_tertiary_fixed_dim": light.TertiaryFixedDim.HexARGB(),
+		"md_theme_inverse_on_surface": light.InverseOnSurface.HexARGB(),
+		"md_theme_on_primary_high_contrast": lightHigh.OnPrimary.
+			HexARGB(),
+		"md_theme_surface_dim_high_contrast": lightHigh.SurfaceDim.
+			HexARGB(),
+	}
+	for name, want := range wants {
+		if got[name] != want {
+			t.Errorf("color %s = %q, want %q", name, got[name], want)
+		}
+	}
+}
+
+func TestCompose(t *testing.T) {
+	light := generate(t)
+	dark := generate(t, material.WithDark(true))
+	darkMedium := generate(
+		t,
+		material.WithDark(true),
+		material.WithContrast(0.5),
+	)
+
+	var sb strings.Builder
+	err := Compose(&sb, "com.example.theme", light, dark, darkMedium)
+	if err != nil {
+		t.Fatalf("Compose() failed: %v", err)
+	}
+	kt := sb.String()
+
+	wants := []string{
+		"package com.example.theme\n",
+		"val primaryLight = " + kotlinColor(light.Primary) + "\n",
+		"val onPrimaryDarkMediumContrast = " +
+			kotlinColor(darkMedium.OnPrimary) + "\n",
+		"val successDark = " +
+			kotlinColor(dark.CustomColors["success"].Color) + "\n",
+		"val lightScheme = lightColorScheme(\n",
+		"val darkScheme = darkColorScheme(\n",
+		"val darkMediumContrastScheme = darkColorScheme(\n",
+		"    inverseOnSurface = inverseOnSurfaceDark,\n",
+		"    val primaryDim: Color,\n",
+		"    val success: ColorFamily,\n",
+		"val extendedLight = ExtendedColorScheme(\n",
+		"    primaryFixedDim = primaryFixedDimLight,\n",
+		"val successFixedLight = " +
+			kotlinColor(light.CustomColors["success"].ColorFixed) + "\n",
+		"data class ColorFamily(\n    val color: Color,\n",
+		"    val onColorFixedVariant: Color,\n    val colorDim: Color,\n)\n",
+		"    success = ColorFamily(\n" +
+			"        color = successDarkMediumContrast,\n" +
+			"        onColor = onSuccessDarkMediumContrast,\n" +
+			"        colorContainer = successContainerDarkMediumContrast,\n" +
+			"        onColorContainer = onSuccessContainerDarkMediumContrast,\n" +
+			"        colorFixed = successFixedDarkMediumContrast,\n" +
+			"        colorFixedDim = successFixedDimDarkMediumContrast,\n" +
+			"        onColorFixed = onSuccessFixedDarkMediumContrast,\n" +
+			"        onColorFixedVariant = " +
+			"onSuccessFixedVariantDarkMediumContrast,\n" +
+			"        colorDim = successDimDarkMediumContrast,\n" +
+			"    ),\n",
+	}
+	for _, want := range wants {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Compose() output doesn't contain %q", want)
+		}
+	}
+
+	if strings.Contains(kt, "    primaryDim = primaryDimLight,\n)") {
+		t.Error("Compose() passes dim role to lightColorScheme")
+	}
+}
+
+func TestComposeVersion2021(t *testing.T) {
+	light := generate(t, material.WithVersion(dynamic.Version2021))
+
+	var sb strings.Builder
+	if err := Compose(&sb, "theme", light); err != nil {
+		t.Fatalf("Compose() failed: %v", err)
+	}
+	kt := sb.String()
+
+	if !strings.Contains(kt, "        colorDim = Color.Unspecified,\n") {
+		t.Error("Compose() doesn't leave dim role of 2021 spec unspecified")
+	}
+	if strings.Contains(kt, "val successDimLight") {
+		t.Error("Compose() generates dim role of 2021 spec")
+	}
+}
+
+func TestAndroidSchemeErrors(t *testing.T) {
+	light := generate(t)
+	dark := generate(t, material.WithDark(true))
+	reduced := generate(t, material.WithContrast(-0.5))
+
+	var sb strings.Builder
+	if err := AndroidXML(&sb, light, dark); err == nil {
+		t.Error("AndroidXML() with light and dark colors returned nil error")
+	}
+	if err := AndroidXML(&sb, light, light); err == nil {
+		t.Error("AndroidXML() with duplicate colors returned nil error")
+	}
+	if err := Compose(&sb, "theme", dark, light, dark); err == nil {
+		t.Error("Compose() with duplicate colors returned nil error")
+	}
+
+	sb.Reset()
+	if err := AndroidXML(&sb, light, reduced); err != nil {
+		t.Fatalf("AndroidXML() failed: %v", err)
+	}
+	want := "md_theme_primary_reduced_contrast\">" + reduced.Primary.HexARGB()
+	if !strings.Contains(sb.String(), want) {
+		t.Errorf("AndroidXML() output does not contain %q", want)
+	}
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Nadim147c/material/v3"
+	"github.com/Nadim147c/material/v3/color"
+)
+
+type cssOptions struct {
+	prefix       string
+	darkSelector string
+	oklch        bool
+}
+
+// CSSOption is a function that modifies the css exporter options.
+type CSSOption func(*cssOptions)
+
+// WithCSSPrefix sets the prefix of the custom property names. Default prefix is
+// "md-sys-color", which produces properties like --md-sys-color-on-primary.
+func WithCSSPrefix(prefix string) CSSOption {
+	return func(o *cssOptions) { o.prefix = prefix }
+}
+
+// WithCSSDarkSelector sets a selector (e.g. "[data-theme=dark]") for the dark
+// colors. By default dark colors are placed inside a
+// "@media (prefers-color-scheme: dark)" block.
+func WithCSSDarkSelector(selector string) CSSOption {
+	return func(o *cssOptions) { o.darkSelector = selector }
+}
+
+// WithCSSOkLch emits colors as oklch() values instead of hex values.
+func WithCSSOkLch() CSSOption {
+	return func(o *cssOptions) { o.oklch = true }
+}
+
+// CSS writes light and dark colors as a stylesheet of CSS custom properties.
+// Light colors are placed under :root and dark colors are placed under
+// "@media (prefers-color-scheme: dark)" or the selector set by
+// WithCSSDarkSelector. Either light or dark can be nil.
+//
+// Property names are derived from the snake case role names, e.g.
+// on_primary_container becomes --md-sys-color-on-primary-container.
+func CSS(
+	w io.Writer,
+	light, dark *material.Colors,
+	options ...CSSOption,
+) error {
+	opts := cssOptions{prefix: "md-sys-color"}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	if light != nil {
+		writeCSSBlock(bw, ":root", "", light, opts)
+	}
+
+	if dark != nil {
+		if light != nil {
+			bw.WriteString("\n")
+		}
+		if opts.darkSelector != "" {
+			writeCSSBlock(bw, opts.darkSelector, "", dark, opts)
+		} else {
+			bw.WriteString("@media (prefers-color-scheme: dark) {\n")
+			writeCSSBlock(bw, ":root", "  ", dark, opts)
+			bw.WriteString("}\n")
+		}
+	}
+
+	return bw.Flush()
+}
+
+func writeCSSBlock(
+	w *bufio.Writer,
+	selector, indent string,
+	colors *material.Colors,
+	opts cssOptions,
+) {
+	m := colors.Map()
+
+	fmt.Fprintf(w, "%s%s {\n", indent, selector)
+	for _, name := range sortedKeys(m) {
+		fmt.Fprintf(w, "%s  --%s: %s;\n",
+			indent, cssPropertyName(opts.prefix, name), cssValue(m[name], opts))
+	}
+	fmt.Fprintf(w, "%s}\n", indent)
+}
+
+// cssPropertyName converts snake case role name to a kebab case custom property
+// name.
+func cssPropertyName(prefix, name string) string {
+	name = strings.ReplaceAll(name, "_", "-")
+	if prefix == "" {
+		return name
+	}
+	return prefix + "-" + name
+}
+
+func cssValue(c color.ARGB, opts cssOptions) string {
+	if !opts.oklch {
+		return c.HexRGB()
+	}
+	lch := c.ToOkLch()
+	return fmt.Sprintf("oklch(%.2f%% %.4f %.2f)",
+		lch.Lightness, lch.Chroma/100, lch.Hue)
+}
@@ -0,0 +1,83 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Nadim147c/material/v3"
+	"github.com/Nadim147c/material/v3/dynamic"
+	"github.com/Nadim147c/material/v3/palettes"
+)
+
+func TestCSS(t *testing.T) {
+	light := generate(t)
+	dark := generate(t, material.WithDark(true))
+
+	var sb strings.Builder
+	if err := CSS(&sb, light, dark); err != nil {
+		t.Fatalf("CSS() failed: %v", err)
+	}
+	css := sb.String()
+
+	wants := []string{
+		":root {\n",
+		"@media (prefers-color-scheme: dark) {\n  :root {\n",
+		"  --md-sys-color-primary: " + light.Primary.HexRGB() + ";\n",
+		"    --md-sys-color-primary: " + dark.Primary.HexRGB() + ";\n",
+		"--md-sys-color-on-primary-container: ",
+		"--md-sys-color-success: " + light.CustomColors["success"].Color.HexRGB(),
+		"--md-sys-color-on-success-container: ",
+	}
+	for _, want := range wants {
+		if !strings.Contains(css, want) {
+			t.Errorf("CSS() output doesn't contain %q:\n%s", want, css)
+		}
+	}
+}
+
+func TestCSSWithColor(t *testing.T) {
+	link := &dynamic.Color{
+		Name: "link",
+		Palette: func(s *dynamic.Scheme) palettes.TonalPalette {
+			return s.PrimaryPalette
+		},
+		Tone: func(*dynamic.Scheme) float64 { return 40 },
+	}
+	light := generate(t, material.WithColor(link))
+
+	var sb strings.Builder
+	if err := CSS(&sb, light, nil); err != nil {
+		t.Fatalf("CSS() failed: %v", err)
+	}
+	css := sb.String()
+
+	want := "--md-sys-color-link: " + light.Extra["link"].HexRGB() + ";\n"
+	if !strings.Contains(css, want) {
+		t.Errorf("CSS() output doesn't contain %q:\n%s", want, css)
+	}
+}
+
+func TestCSSOptions(t *testing.T) {
+	dark := generate(t, material.WithDark(true))
+
+	var sb strings.Builder
+	err := CSS(&sb, nil, dark,
+		WithCSSPrefix("theme"),
+		WithCSSDarkSelector("[data-theme=dark]"),
+		WithCSSOkLch(),
+	)
+	if err != nil {
+		t.Fatalf("CSS() failed: %v", err)
+	}
+	css := sb.String()
+
+	if !strings.HasPrefix(css, "[data-theme=dark] {\n") {
+		t.Errorf("CSS() output doesn't start with dark selector:\n%s", css)
+	}
+	if strings.Contains(css, ":root") {
+		t.Errorf("CSS() output contains :root without light colors:\n%s", css)
+	}
+	if !strings.Contains(css, "  --theme-primary: oklch(") {
+		t.Errorf("CSS() output doesn't contain oklch primary:\n%s", css)
+	}
+}
@@ -0,0 +1,131 @@
+// Package export serializes generated material colors into formats consumed by
+// other platforms and tools.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/Nadim147c/material/v3"
+	"github.com/Nadim147c/material/v3/color"
+)
+
+var (
+	errNoScheme        = errors.New("colors has no scheme")
+	errDuplicateScheme = errors.New("colors have the same mode and contrast")
+	errMixedModes      = errors.New("colors have both light and dark mode")
+)
+
+// customRoles is the roles of a custom color family in order, as snake case
+// formats of the custom color name and the color of the role. Roles which are
+// not available in the scheme version have a zero color.
+var customRoles = []struct {
+	format string
+	color  func(c material.CustomColor) color.ARGB
+}{
+	{"%s", func(c material.CustomColor) color.ARGB {
+		return c.Color
+	}},
+	{"on_%s", func(c material.CustomColor) color.ARGB {
+		return c.OnColor
+	}},
+	{"%s_container", func(c material.CustomColor) color.ARGB {
+		return c.ColorContainer
+	}},
+	{"on_%s_container", func(c material.CustomColor) color.ARGB {
+		return c.OnColorContainer
+	}},
+	{"%s_fixed", func(c material.CustomColor) color.ARGB {
+		return c.ColorFixed
+	}},
+	{"%s_fixed_dim", func(c material.CustomColor) color.ARGB {
+		return c.ColorFixedDim
+	}},
+	{"on_%s_fixed", func(c material.CustomColor) color.ARGB {
+		return c.OnColorFixed
+	}},
+	{"on_%s_fixed_variant", func(c material.CustomColor) color.ARGB {
+		return c.OnColorFixedVariant
+	}},
+	{"%s_dim", func(c material.CustomColor) color.ARGB {
+		return c.ColorDim
+	}},
+}
+
+// schemeRoles returns the roles of colors without custom colors as a map of
+// snake case role name to color. Roles which are not available in the scheme
+// version are omitted.
+func schemeRoles(colors *material.Colors) map[string]color.ARGB {
+	c := *colors
+	c.CustomColors = nil
+	return c.Map()
+}
+
+// schemeName returns the name of the scheme of colors in camel case, e.g.
+// light, darkMediumContrast or lightHighContrast.
+func schemeName(colors *material.Colors) (string, error) {
+	if colors.Scheme == nil {
+		return "", errNoScheme
+	}
+
+	name := "light"
+	if colors.Scheme.Dark {
+		name = "dark"
+	}
+	return name + contrastName(colors.Scheme.Contrast), nil
+}
+
+// schemeNames returns the scheme names of colors. Returns an error if two
+// colors have the same name, i.e. the same mode and contrast level.
+func schemeNames(colors []*material.Colors) ([]string, error) {
+	names := make([]string, 0, len(colors))
+	for _, c := range colors {
+		name, err := schemeName(c)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(names, name) {
+			return nil, fmt.Errorf("%w: %s", errDuplicateScheme, name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// contrastName returns the name of the contrast level in camel case. Returns an
+// empty string for the standard contrast level.
+func contrastName(contrast float64) string {
+	switch {
+	case contrast >= 1:
+		return "HighContrast"
+	case contrast >= 0.5:
+		return "MediumContrast"
+	case contrast < 0:
+		return "ReducedContrast"
+	default:
+		return ""
+	}
+}
+
+// identifier converts name into a lower snake case identifier by replacing all
+// characters other than letters and digits with underscores.
+func identifier(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -0,0 +1,23 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/Nadim147c/material/v3"
+	"github.com/Nadim147c/material/v3/color"
+)
+
+// generate returns colors generated from a blue source color with a custom
+// green color.
+func generate(t *testing.T, options ...material.Option) *material.Colors {
+	t.Helper()
+	options = append(
+		options,
+		material.WithCustomColor("success", color.ARGBFromHexMust("#00FF00")),
+	)
+	colors, err := material.Generate(material.FromHex("#0044FF"), options...)
+	if err != nil {
+		t.Fatalf("failed to generate colors: %v", err)
+	}
+	return colors
+}
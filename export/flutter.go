@@ -0,0 +1,223 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/Nadim147c/material/v3"
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/internal/strcase"
+)
+
+// flutterRoles is the list of snake case roles and their non-deprecated
+// Flutter ColorScheme parameter names.
+var flutterRoles = [][2]string{
+	{"primary", "primary"},
+	{"on_primary", "onPrimary"},
+	{"primary_container", "primaryContainer"},
+	{"on_primary_container", "onPrimaryContainer"},
+	{"primary_fixed", "primaryFixed"},
+	{"primary_fixed_dim", "primaryFixedDim"},
+	{"on_primary_fixed", "onPrimaryFixed"},
+	{"on_primary_fixed_variant", "onPrimaryFixedVariant"},
+	{"secondary", "secondary"},
+	{"on_secondary", "onSecondary"},
+	{"secondary_container", "secondaryContainer"},
+	{"on_secondary_container", "onSecondaryContainer"},
+	{"secondary_fixed", "secondaryFixed"},
+	{"secondary_fixed_dim", "secondaryFixedDim"},
+	{"on_secondary_fixed", "onSecondaryFixed"},
+	{"on_secondary_fixed_variant", "onSecondaryFixedVariant"},
+	{"tertiary", "tertiary"},
+	{"on_tertiary", "onTertiary"},
+	{"tertiary_container", "tertiaryContainer"},
+	{"on_tertiary_container", "onTertiaryContainer"},
+	{"tertiary_fixed", "tertiaryFixed"},
+	{"tertiary_fixed_dim", "tertiaryFixedDim"},
+	{"on_tertiary_fixed", "onTertiaryFixed"},
+	{"on_tertiary_fixed_variant", "onTertiaryFixedVariant"},
+	{"error", "error"},
+	{"on_error", "onError"},
+	{"error_container", "errorContainer"},
+	{"on_error_container", "onErrorContainer"},
+	{"surface", "surface"},
+	{"on_surface", "onSurface"},
+	{"surface_dim", "surfaceDim"},
+	{"surface_bright", "surfaceBright"},
+	{"surface_container_lowest", "surfaceContainerLowest"},
+	{"surface_container_low", "surfaceContainerLow"},
+	{"surface_container", "surfaceContainer"},
+	{"surface_container_high", "surfaceContainerHigh"},
+	{"surface_container_highest", "surfaceContainerHighest"},
+	{"on_surface_variant", "onSurfaceVariant"},
+	{"outline", "outline"},
+	{"outline_variant", "outlineVariant"},
+	{"shadow", "shadow"},
+	{"scrim", "scrim"},
+	{"inverse_surface", "inverseSurface"},
+	{"inverse_on_surface", "onInverseSurface"},
+	{"inverse_primary", "inversePrimary"},
+	{"surface_tint", "surfaceTint"},
+}
+
+// Flutter writes colors as a Dart file of Flutter color schemes.
+//
+// For every colors a static ColorScheme named after its mode and contrast
+// level is generated in the MaterialTheme class, e.g. lightScheme,
+// darkMediumContrastScheme or lightHighContrastScheme. Roles registered with
+// material.WithColor are generated as ExtraColors ThemeExtension and custom
+// colors as CustomColors ThemeExtension, with a static instance for every
+// colors, e.g. CustomColors.light or CustomColors.darkHighContrast. Returns
+// an error if two colors have the same mode and contrast level.
+func Flutter(w io.Writer, colors ...*material.Colors) error {
+	type scheme struct {
+		name  string
+		dark  bool
+		roles map[string]color.ARGB
+	}
+
+	names, err := schemeNames(colors)
+	if err != nil {
+		return err
+	}
+
+	schemes := make([]scheme, 0, len(colors))
+	extraRoles := map[string]bool{}
+	customNames := map[string]bool{}
+	extra := make([]map[string]color.ARGB, 0, len(colors))
+	custom := make([]map[string]color.ARGB, 0, len(colors))
+	for i, c := range colors {
+		schemes = append(schemes, scheme{
+			name:  names[i],
+			dark:  c.Scheme.Dark,
+			roles: schemeRoles(c),
+		})
+
+		e := map[string]color.ARGB{}
+		for role, argb := range c.Extra {
+			field := strcase.Camel(identifier(role))
+			e[field] = argb
+			extraRoles[field] = true
+		}
+		extra = append(extra, e)
+
+		cc := map[string]color.ARGB{}
+		for n, family := range c.CustomColors {
+			n = identifier(n)
+			for _, role := range customRoles {
+				if argb := role.color(family); argb != 0 {
+					cc[strcase.Camel(fmt.Sprintf(role.format, n))] = argb
+				}
+			}
+			customNames[n] = true
+		}
+		custom = append(custom, cc)
+	}
+
+	// Every custom color has a field for every role of the color family.
+	var fields []string
+	for _, n := range sortedKeys(customNames) {
+		for _, role := range customRoles {
+			fields = append(fields, strcase.Camel(fmt.Sprintf(role.format, n)))
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("import 'package:flutter/material.dart';\n\n")
+	bw.WriteString("class MaterialTheme {\n")
+	bw.WriteString("  const MaterialTheme._();\n")
+
+	for _, s := range schemes {
+		brightness := "Brightness.light"
+		if s.dark {
+			brightness = "Brightness.dark"
+		}
+
+		fmt.Fprintf(bw, "\n  static const %sScheme = ColorScheme(\n", s.name)
+		fmt.Fprintf(bw, "    brightness: %s,\n", brightness)
+		for _, role := range flutterRoles {
+			if c, ok := s.roles[role[0]]; ok {
+				fmt.Fprintf(bw, "    %s: %s,\n", role[1], dartColor(c))
+			}
+		}
+		bw.WriteString("  );\n")
+	}
+	bw.WriteString("}\n")
+
+	dartExtension(bw, "ExtraColors", sortedKeys(extraRoles), names, extra)
+	dartExtension(bw, "CustomColors", fields, names, custom)
+	return bw.Flush()
+}
+
+// dartExtension writes a Flutter ThemeExtension class named class with a
+// nullable Color field for every field, and a static instance named after
+// every scheme in names with the values of the scheme by field. Fields which
+// a scheme doesn't have are null. Nothing is written without fields.
+func dartExtension(
+	bw *bufio.Writer,
+	class string,
+	fields []string,
+	names []string,
+	values []map[string]color.ARGB,
+) {
+	if len(fields) == 0 {
+		return
+	}
+
+	bw.WriteString("\n@immutable\n")
+	fmt.Fprintf(bw, "class %s extends ThemeExtension<%s> {\n", class, class)
+	fmt.Fprintf(bw, "  const %s({\n", class)
+	for _, f := range fields {
+		fmt.Fprintf(bw, "    required this.%s,\n", f)
+	}
+	bw.WriteString("  });\n\n")
+
+	for _, f := range fields {
+		fmt.Fprintf(bw, "  final Color? %s;\n", f)
+	}
+
+	for i, name := range names {
+		fmt.Fprintf(bw, "\n  static const %s = %s(\n", name, class)
+		for _, f := range fields {
+			value := "null"
+			if c, ok := values[i][f]; ok {
+				value = dartColor(c)
+			}
+			fmt.Fprintf(bw, "    %s: %s,\n", f, value)
+		}
+		bw.WriteString("  );\n")
+	}
+
+	bw.WriteString("\n  @override\n")
+	fmt.Fprintf(bw, "  %s copyWith({\n", class)
+	for _, f := range fields {
+		fmt.Fprintf(bw, "    Color? %s,\n", f)
+	}
+	bw.WriteString("  }) {\n")
+	fmt.Fprintf(bw, "    return %s(\n", class)
+	for _, f := range fields {
+		fmt.Fprintf(bw, "      %s: %s ?? this.%s,\n", f, f, f)
+	}
+	bw.WriteString("    );\n")
+	bw.WriteString("  }\n")
+
+	bw.WriteString("\n  @override\n")
+	fmt.Fprintf(bw, "  %s lerp(ThemeExtension<%s>? other, double t) {\n",
+		class, class)
+	fmt.Fprintf(bw, "    if (other is! %s) {\n", class)
+	bw.WriteString("      return this;\n")
+	bw.WriteString("    }\n")
+	fmt.Fprintf(bw, "    return %s(\n", class)
+	for _, f := range fields {
+		fmt.Fprintf(bw, "      %s: Color.lerp(%s, other.%s, t),\n", f, f, f)
+	}
+	bw.WriteString("    );\n")
+	bw.WriteString("  }\n")
+	bw.WriteString("}\n")
+}
+
+// dartColor returns c as a Flutter Color constructor call.
+func dartColor(c color.ARGB) string {
+	return fmt.Sprintf("Color(0x%08X)", uint32(c))
+}
@@ -0,0 +1,118 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Nadim147c/material/v3"
+	"github.com/Nadim147c/material/v3/dynamic"
+	"github.com/Nadim147c/material/v3/palettes"
+)
+
+func TestFlutter(t *testing.T) {
+	light := generate(t)
+	dark := generate(t, material.WithDark(true))
+	lightHigh := generate(t, material.WithContrast(1))
+
+	var sb strings.Builder
+	if err := Flutter(&sb, light, dark, lightHigh); err != nil {
+		t.Fatalf("Flutter() failed: %v", err)
+	}
+	dart := sb.String()
+
+	success := dark.CustomColors["success"]
+	wants := []string{
+		"import 'package:flutter/material.dart';\n",
+		"  static const lightScheme = ColorScheme(\n" +
+			"    brightness: Brightness.light,\n" +
+			"    primary: " + dartColor(light.Primary) + ",\n",
+		"  static const darkScheme = ColorScheme(\n" +
+			"    brightness: Brightness.dark,\n",
+		"  static const lightHighContrastScheme = ColorScheme(\n",
+		"    onInverseSurface: " + dartColor(dark.InverseOnSurface) + ",\n",
+		"    primaryFixedDim: " + dartColor(light.PrimaryFixedDim) + ",\n",
+		"class CustomColors extends ThemeExtension<CustomColors> {\n",
+		"    required this.onSuccessContainer,\n",
+		"  final Color? successContainer;\n",
+		"  static const dark = CustomColors(\n" +
+			"    success: " + dartColor(success.Color) + ",\n" +
+			"    onSuccess: " + dartColor(success.OnColor) + ",\n",
+		"    onSuccessContainer: " + dartColor(success.OnColorContainer) + ",\n" +
+			"    successFixed: " + dartColor(success.ColorFixed) + ",\n" +
+			"    successFixedDim: " + dartColor(success.ColorFixedDim) + ",\n" +
+			"    onSuccessFixed: " + dartColor(success.OnColorFixed) + ",\n" +
+			"    onSuccessFixedVariant: " +
+			dartColor(success.OnColorFixedVariant) + ",\n" +
+			"    successDim: " + dartColor(success.ColorDim) + ",\n",
+		"    required this.successFixedDim,\n",
+		"  final Color? onSuccessFixedVariant;\n",
+		"      success: success ?? this.success,\n",
+		"      successFixed: successFixed ?? this.successFixed,\n",
+		"      onSuccess: Color.lerp(onSuccess, other.onSuccess, t),\n",
+		"      successDim: Color.lerp(successDim, other.successDim, t),\n",
+	}
+	for _, want := range wants {
+		if !strings.Contains(dart, want) {
+			t.Errorf("Flutter() output doesn't contain %q", want)
+		}
+	}
+
+	for _, deprecated := range []string{"    background:", "surfaceVariant:"} {
+		if strings.Contains(dart, deprecated) {
+			t.Errorf("Flutter() output contains deprecated %q", deprecated)
+		}
+	}
+
+	if err := Flutter(&sb, light, dark, light); err == nil {
+		t.Error("Flutter() with duplicate colors returned nil error")
+	}
+}
+
+func TestFlutterWithColor(t *testing.T) {
+	link := &dynamic.Color{
+		Name: "link",
+		Palette: func(s *dynamic.Scheme) palettes.TonalPalette {
+			return s.PrimaryPalette
+		},
+		Tone: func(*dynamic.Scheme) float64 { return 40 },
+	}
+	light := generate(t, material.WithColor(link))
+	dark := generate(t, material.WithDark(true))
+
+	var sb strings.Builder
+	if err := Flutter(&sb, light, dark); err != nil {
+		t.Fatalf("Flutter() failed: %v", err)
+	}
+	dart := sb.String()
+
+	wants := []string{
+		"class ExtraColors extends ThemeExtension<ExtraColors> {\n",
+		"    required this.link,\n",
+		"  final Color? link;\n",
+		"  static const light = ExtraColors(\n" +
+			"    link: " + dartColor(light.Extra["link"]) + ",\n",
+		"  static const dark = ExtraColors(\n    link: null,\n",
+		"      link: link ?? this.link,\n",
+		"      link: Color.lerp(link, other.link, t),\n",
+	}
+	for _, want := range wants {
+		if !strings.Contains(dart, want) {
+			t.Errorf("Flutter() output doesn't contain %q", want)
+		}
+	}
+}
+
+func TestFlutterWithoutCustomColors(t *testing.T) {
+	colors, err := material.Generate(material.FromHex("#0044FF"))
+	if err != nil {
+		t.Fatalf("failed to generate colors: %v", err)
+	}
+
+	var sb strings.Builder
+	if err := Flutter(&sb, colors); err != nil {
+		t.Fatalf("Flutter() failed: %v", err)
+	}
+	if strings.Contains(sb.String(), "ThemeExtension") {
+		t.Error("Flutter() generated theme extension without custom colors")
+	}
+}
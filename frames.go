@@ -0,0 +1,213 @@
+package material
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+// defaultGIFDelay is the delay of GIF frames without a usable delay. Like most
+// browsers, delays less than 20ms are treated as 100ms.
+const defaultGIFDelay = 100 * time.Millisecond
+
+// Frame is a frame of an animation or a video.
+type Frame struct {
+	Source Source
+	// Delay is the duration the frame is displayed for.
+	Delay time.Duration
+}
+
+// GIFFrames returns the frames of g. Each frame is composited on top of the
+// previous frames according to the disposal methods of g, so frames contain
+// the whole picture displayed at that time. Options are used for the Source of
+// each frame, see FromImage.
+func GIFFrames(g *gif.GIF, options ...ImageOption) []Frame {
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() {
+		for _, img := range g.Image {
+			bounds = bounds.Union(img.Bounds())
+		}
+	}
+
+	canvas := image.NewRGBA(bounds)
+	frames := make([]Frame, 0, len(g.Image))
+	for i, img := range g.Image {
+		var disposal byte
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		var previous []uint8
+		if disposal == gif.DisposalPrevious {
+			previous = slices.Clone(canvas.Pix)
+		}
+
+		draw.Draw(canvas, img.Bounds(), img, img.Bounds().Min, draw.Over)
+
+		frame := image.NewRGBA(bounds)
+		copy(frame.Pix, canvas.Pix)
+
+		delay := defaultGIFDelay
+		if i < len(g.Delay) && g.Delay[i] >= 2 {
+			delay = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+		}
+
+		frames = append(frames, Frame{
+			Source: FromImage(frame, options...),
+			Delay:  delay,
+		})
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, img.Bounds(), image.Transparent, image.Point{},
+				draw.Src)
+		case gif.DisposalPrevious:
+			copy(canvas.Pix, previous)
+		}
+	}
+
+	return frames
+}
+
+// RawFrames reads frames of raw r, g, b bytes (3 bytes per color) of width x
+// height pixels from r until EOF, e.g. the rawvideo output of ffmpeg with
+// rgb24 pixel format. Every frame is displayed for delay.
+func RawFrames(
+	r io.Reader,
+	width, height int,
+	delay time.Duration,
+) ([]Frame, error) {
+	size := width * height * 3
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid frame size %dx%d", width, height)
+	}
+
+	var frames []Frame
+	for {
+		buf := make([]byte, size)
+		_, err := io.ReadFull(r, buf)
+		if errors.Is(err, io.EOF) {
+			return frames, nil
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf(
+				"frame %d is incomplete: %w", len(frames), err,
+			)
+		}
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, Frame{Source: FromBytes(buf), Delay: delay})
+	}
+}
+
+// FromFrames returns WeightedSource colors of all frames. Colors of each frame
+// are weighted by the delay of the frame relative to the average delay, so the
+// frames displayed longer have more effect on the colors. All frames have
+// weight of 1 if every delay is zero.
+func FromFrames(frames []Frame) WeightedSource {
+	return func() ([]color.ARGB, []float64, error) {
+		var total time.Duration
+		for _, f := range frames {
+			total += max(f.Delay, 0)
+		}
+
+		var colors []color.ARGB
+		var weights []float64
+		for _, f := range frames {
+			c, err := f.Source()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			weight := 1.0
+			if total > 0 {
+				weight = float64(max(f.Delay, 0)) * float64(len(frames)) /
+					float64(total)
+			}
+
+			colors = append(colors, c...)
+			for range c {
+				weights = append(weights, weight)
+			}
+		}
+		return colors, weights, nil
+	}
+}
+
+// Keyframe is the source color of a frame in a timeline.
+type Keyframe struct {
+	// Time is the time the frame is displayed at.
+	Time time.Duration `json:"time"`
+	// Duration is the duration the frame is displayed for.
+	Duration time.Duration `json:"duration"`
+	// Frame is the source color of the frame itself.
+	Frame color.ARGB `json:"frame"`
+	// Source is the source color smoothed over the previous frames.
+	Source color.ARGB `json:"source"`
+}
+
+// Timeline returns a keyframe for each frame. The source color of each frame
+// is resolved with the options and smoothed over time in OkLab color space
+// with an exponential moving average, so source colors change gradually
+// instead of flickering between frames. After a change, the smoothed color
+// moves 63% of the way to the new color in smoothing and 95% of the way in
+// three times smoothing. Source colors aren't smoothed if smoothing is not
+// positive.
+func Timeline(
+	frames []Frame,
+	smoothing time.Duration,
+	options ...Option,
+) ([]Keyframe, error) {
+	cfg := newSettings(options)
+
+	var at time.Duration
+	var smoothed color.OkLab
+	keyframes := make([]Keyframe, 0, len(frames))
+	for i, f := range frames {
+		if err := cfg.Context.Err(); err != nil {
+			return nil, err
+		}
+
+		colors, err := f.Source()
+		if err != nil {
+			return nil, err
+		}
+
+		source, _, err := sourceColor(cfg, colors, nil)
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: %w", i, err)
+		}
+
+		lab := source.ToOkLab()
+		if i == 0 || smoothing <= 0 {
+			smoothed = lab
+		} else {
+			elapsed := frames[i-1].Delay
+			a := 1 - math.Exp(-float64(elapsed)/float64(smoothing))
+			smoothed = color.NewOkLab(
+				smoothed.L+a*(lab.L-smoothed.L),
+				smoothed.A+a*(lab.A-smoothed.A),
+				smoothed.B+a*(lab.B-smoothed.B),
+			)
+		}
+
+		keyframes = append(keyframes, Keyframe{
+			Time:     at,
+			Duration: f.Delay,
+			Frame:    source,
+			Source:   smoothed.ToARGB(),
+		})
+		at += f.Delay
+	}
+
+	return keyframes, nil
+}
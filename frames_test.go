@@ -0,0 +1,186 @@
+package material
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	gocolor "image/color"
+	"image/gif"
+	"io"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+func TestGIFFrames(t *testing.T) {
+	p := gocolor.Palette{
+		gocolor.RGBA{R: 0xFF, A: 0xFF},
+		gocolor.RGBA{B: 0xFF, A: 0xFF},
+		gocolor.RGBA{G: 0xFF, A: 0xFF},
+	}
+	red, blue, green := color.ARGB(0xFFFF0000), color.ARGB(0xFF0000FF),
+		color.ARGB(0xFF00FF00)
+
+	frame := func(r image.Rectangle, i uint8) *image.Paletted {
+		img := image.NewPaletted(r, p)
+		for j := range img.Pix {
+			img.Pix[j] = i
+		}
+		return img
+	}
+
+	g := &gif.GIF{
+		Image: []*image.Paletted{
+			frame(image.Rect(0, 0, 2, 2), 0),
+			frame(image.Rect(0, 0, 1, 1), 1),
+			frame(image.Rect(1, 1, 2, 2), 2),
+			frame(image.Rect(1, 0, 2, 1), 2),
+		},
+		Delay: []int{0, 5, 10, 1},
+		Disposal: []byte{
+			gif.DisposalNone,
+			gif.DisposalPrevious,
+			gif.DisposalBackground,
+			gif.DisposalNone,
+		},
+		Config: image.Config{Width: 2, Height: 2},
+	}
+
+	want := []struct {
+		pixels []color.ARGB
+		delay  time.Duration
+	}{
+		{[]color.ARGB{red, red, red, red}, 100 * time.Millisecond},
+		{[]color.ARGB{blue, red, red, red}, 50 * time.Millisecond},
+		{[]color.ARGB{red, red, red, green}, 100 * time.Millisecond},
+		{[]color.ARGB{red, green, red, 0}, 100 * time.Millisecond},
+	}
+
+	frames := GIFFrames(g)
+	if len(frames) != len(want) {
+		t.Fatalf("GIFFrames() returned %d frames, want %d",
+			len(frames), len(want))
+	}
+	for i, f := range frames {
+		got, err := f.Source()
+		if err != nil {
+			t.Fatalf("frame %d failed: %v", i, err)
+		}
+		if !slices.Equal(got, want[i].pixels) {
+			t.Errorf("frame %d = %v, want %v", i, got, want[i].pixels)
+		}
+		if f.Delay != want[i].delay {
+			t.Errorf("frame %d delay = %s, want %s", i, f.Delay, want[i].delay)
+		}
+	}
+}
+
+func TestRawFrames(t *testing.T) {
+	data := []byte{0xFF, 0, 0, 0xFF, 0, 0, 0, 0, 0xFF, 0, 0, 0xFF}
+
+	frames, err := RawFrames(bytes.NewReader(data), 2, 1, time.Second)
+	if err != nil {
+		t.Fatalf("RawFrames() failed: %v", err)
+	}
+	if len(frames) != 2 {
+		t.Fatalf("RawFrames() returned %d frames, want 2", len(frames))
+	}
+
+	got, _ := frames[1].Source()
+	want := []color.ARGB{0xFF0000FF, 0xFF0000FF}
+	if !slices.Equal(got, want) {
+		t.Errorf("frame 1 = %v, want %v", got, want)
+	}
+
+	_, err = RawFrames(bytes.NewReader(data[:9]), 2, 1, time.Second)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("RawFrames() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestFromFrames(t *testing.T) {
+	frames := []Frame{
+		{FromHexes([]string{"#FF0000", "#FF0000"}), 300 * time.Millisecond},
+		{FromHex("#0000FF"), 100 * time.Millisecond},
+	}
+
+	_, weights, err := FromFrames(frames)()
+	if err != nil {
+		t.Fatalf("FromFrames() failed: %v", err)
+	}
+	want := []float64{1.5, 1.5, 0.5}
+	if !slices.Equal(weights, want) {
+		t.Errorf("FromFrames() weights = %v, want %v", weights, want)
+	}
+
+	frames[0].Delay, frames[1].Delay = 0, 0
+	_, weights, _ = FromFrames(frames)()
+	if want := []float64{1, 1, 1}; !slices.Equal(weights, want) {
+		t.Errorf("FromFrames() weights = %v, want %v", weights, want)
+	}
+
+	colors, err := GenerateWeighted(FromFrames(frames), WithSeed(1))
+	if err != nil {
+		t.Fatalf("failed to generate colors: %v", err)
+	}
+	if len(colors.Candidates) == 0 {
+		t.Errorf("GenerateWeighted() returned no candidates")
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	red := color.ARGB(0xFFFF0000)
+	blue := color.ARGB(0xFF0000FF)
+
+	var frames []Frame
+	for i := range 6 {
+		c := red
+		if i >= 3 {
+			c = blue
+		}
+		frames = append(frames, Frame{
+			Source: FromARGB([]color.ARGB{c}),
+			Delay:  100 * time.Millisecond,
+		})
+	}
+
+	keyframes, err := Timeline(frames, 0)
+	if err != nil {
+		t.Fatalf("Timeline() failed: %v", err)
+	}
+	for i, k := range keyframes {
+		if k.Source != k.Frame {
+			t.Errorf("keyframe %d is smoothed without smoothing", i)
+		}
+		if want := time.Duration(i) * 100 * time.Millisecond; k.Time != want {
+			t.Errorf("keyframe %d time = %s, want %s", i, k.Time, want)
+		}
+	}
+
+	keyframes, err = Timeline(frames, time.Second)
+	if err != nil {
+		t.Fatalf("Timeline() failed: %v", err)
+	}
+	if keyframes[2].Source != red || keyframes[3].Frame != blue {
+		t.Fatalf("Timeline() = %v", keyframes)
+	}
+
+	distance := func(a, b color.ARGB) float64 {
+		x, y := a.ToOkLab(), b.ToOkLab()
+		return math.Hypot(x.L-y.L, math.Hypot(x.A-y.A, x.B-y.B))
+	}
+
+	// The smoothed color moves towards blue a little on every frame.
+	previous := distance(red, blue)
+	for _, k := range keyframes[3:] {
+		d := distance(k.Source, blue)
+		if d <= 0 || d >= previous {
+			t.Errorf("keyframe at %s is %s, want between red and blue",
+				k.Time, k.Source)
+		}
+		previous = d
+	}
+}
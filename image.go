@@ -0,0 +1,289 @@
+package material
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	gocolor "image/color"
+	_ "image/gif"  // register gif decoder
+	_ "image/jpeg" // register jpeg decoder
+	_ "image/png"  // register png decoder
+	"io"
+	"math"
+	"os"
+	"slices"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+// imageOptions configures how pixels are sampled from an image
+type imageOptions struct {
+	maxPixels      int
+	stride         int
+	alphaThreshold uint8
+}
+
+// ImageOption is a function that modifies the image sampling options
+type ImageOption func(*imageOptions)
+
+// WithMaxPixels returns an ImageOption that limits the number of returned
+// pixels to n. Larger images are downscaled by averaging boxes of pixels.
+func WithMaxPixels(n int) ImageOption {
+	return func(o *imageOptions) { o.maxPixels = n }
+}
+
+// WithSampleStride returns an ImageOption that only reads every k-th pixel of
+// every k-th row. When used with WithMaxPixels, only the sampled pixels of a
+// box are averaged.
+func WithSampleStride(k int) ImageOption {
+	return func(o *imageOptions) { o.stride = k }
+}
+
+// WithAlphaThreshold returns an ImageOption that skips pixels with alpha less
+// than a, e.g. the transparent background of icons and logos. When used with
+// WithMaxPixels, skipped pixels are excluded from the box averages and boxes
+// without any remaining pixel are skipped.
+func WithAlphaThreshold(a uint8) ImageOption {
+	return func(o *imageOptions) { o.alphaThreshold = a }
+}
+
+// FromImage returns Source colors from image.Image interface. Pixels of
+// *image.RGBA, *image.NRGBA, *image.YCbCr, *image.Paletted and *image.Gray are
+// read from their Pix slice directly.
+//
+// Options can be used to downsample large images. Sampling is deterministic,
+// same image always returns same pixels.
+func FromImage(img image.Image, options ...ImageOption) Source {
+	var opts imageOptions
+	for opt := range slices.Values(options) {
+		opt(&opts)
+	}
+
+	return func() ([]color.ARGB, error) {
+		pixels, _ := readImage(img, opts, nil)
+		return pixels, nil
+	}
+}
+
+// FromImageReader returns Source colors from an encoded image. PNG, JPEG and
+// GIF decoders are registered, other formats can be used by registering their
+// decoder with image.RegisterFormat. The image is decoded when the Source is
+// called. Options are same as FromImage.
+func FromImageReader(r io.Reader, options ...ImageOption) Source {
+	return func() ([]color.ARGB, error) {
+		img, err := decodeImage(r)
+		if err != nil {
+			return nil, err
+		}
+		return FromImage(img, options...)()
+	}
+}
+
+// FromImageFile returns Source colors from an encoded image file. See
+// FromImageReader for supported formats.
+func FromImageFile(path string, options ...ImageOption) Source {
+	return func() ([]color.ARGB, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		img, err := decodeImage(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return FromImage(img, options...)()
+	}
+}
+
+// decodeImage decodes an image with the registered decoders. Unknown formats
+// return an error wrapping image.ErrFormat.
+func decodeImage(r io.Reader) (image.Image, error) {
+	img, format, err := image.Decode(r)
+	if errors.Is(err, image.ErrFormat) {
+		return nil, fmt.Errorf(
+			"%w: not a png, jpeg, gif or another registered image format",
+			err,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s image: %w", format, err)
+	}
+	return img, nil
+}
+
+// readImage returns the sampled pixels of img and their weights. Weights are
+// nil if weight is nil.
+func readImage(
+	img image.Image,
+	opts imageOptions,
+	weight WeightFunc,
+) ([]color.ARGB, []float64) {
+	bounds := img.Bounds()
+	at := pixelReader(img)
+
+	var pixels []color.ARGB
+	var weights []float64
+	add := func(c color.ARGB, w float64) {
+		pixels = append(pixels, c)
+		if weight != nil {
+			weights = append(weights, w)
+		}
+	}
+
+	stride := max(opts.stride, 1)
+	box := max(stride, boxSize(bounds.Dx(), bounds.Dy(), opts.maxPixels))
+	if box == 1 {
+		pixels = make([]color.ARGB, 0, bounds.Dx()*bounds.Dy())
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				c := at(x, y)
+				if c.Alpha() < opts.alphaThreshold {
+					continue
+				}
+				var w float64
+				if weight != nil {
+					w = weight(x, y)
+				}
+				add(c, w)
+			}
+		}
+		return pixels, weights
+	}
+
+	size := ceilDiv(bounds.Dx(), box) * ceilDiv(bounds.Dy(), box)
+	pixels = make([]color.ARGB, 0, size)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += box {
+		for x := bounds.Min.X; x < bounds.Max.X; x += box {
+			r := image.Rect(x, y, x+box, y+box).Intersect(bounds)
+			c, w, ok := averageBox(at, weight, r, stride, opts.alphaThreshold)
+			if ok {
+				add(c, w)
+			}
+		}
+	}
+	return pixels, weights
+}
+
+// boxSize returns the smallest box size which downscales a width x height image
+// into at most maxPixels pixels. Returns 1 if maxPixels is not positive.
+func boxSize(width, height, maxPixels int) int {
+	if maxPixels <= 0 || width*height <= maxPixels {
+		return 1
+	}
+
+	area := float64(width * height)
+	box := int(math.Ceil(math.Sqrt(area / float64(maxPixels))))
+	for ceilDiv(width, box)*ceilDiv(height, box) > maxPixels {
+		box++
+	}
+	return box
+}
+
+// averageBox returns the average color of every stride-th pixel of every
+// stride-th row in r and the average weight of these pixels. Pixels with alpha
+// less than threshold are skipped. Returns false if every pixel is skipped.
+func averageBox(
+	at func(x, y int) color.ARGB,
+	weight WeightFunc,
+	r image.Rectangle,
+	stride int,
+	threshold uint8,
+) (color.ARGB, float64, bool) {
+	var a, red, green, blue, n uint64
+	var w float64
+	for y := r.Min.Y; y < r.Max.Y; y += stride {
+		for x := r.Min.X; x < r.Max.X; x += stride {
+			c := at(x, y)
+			if c.Alpha() < threshold {
+				continue
+			}
+			a += uint64(c.Alpha())
+			red += uint64(c.Red())
+			green += uint64(c.Green())
+			blue += uint64(c.Blue())
+			if weight != nil {
+				w += weight(x, y)
+			}
+			n++
+		}
+	}
+
+	if n == 0 {
+		return 0, 0, false
+	}
+
+	avg := func(sum uint64) uint8 { return uint8((sum + n/2) / n) }
+	c := color.NewARGB(avg(a), avg(red), avg(green), avg(blue))
+	return c, w / float64(n), true
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+// pixelReader returns a function which returns the color of img at (x, y). The
+// result is same as converting img.At(x, y) with color.ARGBFromInterface, but
+// it avoids the interface conversion for the common image types.
+func pixelReader(img image.Image) func(x, y int) color.ARGB {
+	switch img := img.(type) {
+	case *image.RGBA:
+		return func(x, y int) color.ARGB {
+			i := img.PixOffset(x, y)
+			s := img.Pix[i : i+4 : i+4]
+			return color.NewARGB(s[3], s[0], s[1], s[2])
+		}
+	case *image.NRGBA:
+		return func(x, y int) color.ARGB {
+			i := img.PixOffset(x, y)
+			s := img.Pix[i : i+4 : i+4]
+			a := uint32(s[3])
+			return color.NewARGB(
+				s[3],
+				premultiply(s[0], a),
+				premultiply(s[1], a),
+				premultiply(s[2], a),
+			)
+		}
+	case *image.YCbCr:
+		return func(x, y int) color.ARGB {
+			yi := img.YOffset(x, y)
+			ci := img.COffset(x, y)
+			c := gocolor.YCbCr{Y: img.Y[yi], Cb: img.Cb[ci], Cr: img.Cr[ci]}
+			r, g, b, _ := c.RGBA()
+			return color.NewARGB(0xFF, uint8(r>>8), uint8(g>>8), uint8(b>>8))
+		}
+	case *image.Paletted:
+		palette := make([]color.ARGB, len(img.Palette))
+		for i, c := range img.Palette {
+			palette[i] = color.ARGBFromInterface(c)
+		}
+		return func(x, y int) color.ARGB {
+			i := img.Pix[img.PixOffset(x, y)]
+			if int(i) >= len(palette) {
+				return 0
+			}
+			return palette[i]
+		}
+	case *image.Gray:
+		return func(x, y int) color.ARGB {
+			v := img.Pix[img.PixOffset(x, y)]
+			return color.NewARGB(0xFF, v, v, v)
+		}
+	default:
+		return func(x, y int) color.ARGB {
+			return color.ARGBFromInterface(img.At(x, y))
+		}
+	}
+}
+
+// premultiply returns the 8-bit premultiplied value of the non-premultiplied
+// 8-bit channel c with alpha a, rounded the same way as gocolor.NRGBA.RGBA.
+func premultiply(c uint8, a uint32) uint8 {
+	v := uint32(c)
+	v |= v << 8
+	v *= a
+	v /= 0xFF
+	return uint8(v >> 8)
+}
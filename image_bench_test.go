@@ -0,0 +1,78 @@
+package material
+
+import (
+	"testing"
+)
+
+func benchmarkFromImage(b *testing.B, name string, generic bool) {
+	img := randomImages(1920, 1080)[name]
+	if generic {
+		img = genericImage{img}
+	}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, _ = FromImage(img)()
+	}
+}
+
+func BenchmarkFromImage_RGBA(b *testing.B) {
+	benchmarkFromImage(b, "rgba", false)
+}
+
+func BenchmarkFromImage_RGBAGeneric(b *testing.B) {
+	benchmarkFromImage(b, "rgba", true)
+}
+
+func BenchmarkFromImage_NRGBA(b *testing.B) {
+	benchmarkFromImage(b, "nrgba", false)
+}
+
+func BenchmarkFromImage_NRGBAGeneric(b *testing.B) {
+	benchmarkFromImage(b, "nrgba", true)
+}
+
+func BenchmarkFromImage_YCbCr(b *testing.B) {
+	benchmarkFromImage(b, "ycbcr420", false)
+}
+
+func BenchmarkFromImage_YCbCrGeneric(b *testing.B) {
+	benchmarkFromImage(b, "ycbcr420", true)
+}
+
+func BenchmarkFromImage_Paletted(b *testing.B) {
+	benchmarkFromImage(b, "paletted", false)
+}
+
+func BenchmarkFromImage_PalettedGeneric(b *testing.B) {
+	benchmarkFromImage(b, "paletted", true)
+}
+
+func BenchmarkFromImage_Gray(b *testing.B) {
+	benchmarkFromImage(b, "gray", false)
+}
+
+func BenchmarkFromImage_GrayGeneric(b *testing.B) {
+	benchmarkFromImage(b, "gray", true)
+}
+
+func BenchmarkFromImage_MaxPixels(b *testing.B) {
+	img := randomImages(3840, 2160)["ycbcr420"]
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, _ = FromImage(img, WithMaxPixels(256*256))()
+	}
+}
+
+func BenchmarkFromImage_SampleStride(b *testing.B) {
+	img := randomImages(3840, 2160)["ycbcr420"]
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, _ = FromImage(img, WithSampleStride(8))()
+	}
+}
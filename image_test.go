@@ -0,0 +1,286 @@
+package material
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	gocolor "image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+// genericImage hides the concrete type of the image to force the generic
+// img.At path.
+type genericImage struct {
+	image.Image
+}
+
+func randomImages(width, height int) map[string]image.Image {
+	r := rand.New(rand.NewSource(1))
+	rect := image.Rect(0, 0, width, height)
+
+	rgba := image.NewRGBA(rect)
+	nrgba := image.NewNRGBA(rect)
+	gray := image.NewGray(rect)
+	paletted := image.NewPaletted(rect, palette.Plan9)
+	r.Read(rgba.Pix)
+	r.Read(nrgba.Pix)
+	r.Read(gray.Pix)
+	r.Read(paletted.Pix)
+
+	// RGBA must be premultiplied.
+	for i := 0; i < len(rgba.Pix); i += 4 {
+		a := rgba.Pix[i+3]
+		for j := range 3 {
+			rgba.Pix[i+j] = min(rgba.Pix[i+j], a)
+		}
+	}
+
+	images := map[string]image.Image{
+		"rgba":     rgba,
+		"nrgba":    nrgba,
+		"gray":     gray,
+		"paletted": paletted,
+		"sub":      nrgba.SubImage(image.Rect(3, 5, width-7, height-2)),
+	}
+
+	ratios := map[string]image.YCbCrSubsampleRatio{
+		"ycbcr444": image.YCbCrSubsampleRatio444,
+		"ycbcr422": image.YCbCrSubsampleRatio422,
+		"ycbcr420": image.YCbCrSubsampleRatio420,
+	}
+	for name, ratio := range ratios {
+		ycbcr := image.NewYCbCr(rect, ratio)
+		r.Read(ycbcr.Y)
+		r.Read(ycbcr.Cb)
+		r.Read(ycbcr.Cr)
+		images[name] = ycbcr
+	}
+
+	return images
+}
+
+func TestFromImage(t *testing.T) {
+	for name, img := range randomImages(64, 48) {
+		t.Run(name, func(t *testing.T) {
+			got, err := FromImage(img)()
+			if err != nil {
+				t.Fatalf("FromImage() failed: %v", err)
+			}
+
+			want, err := FromImage(genericImage{img})()
+			if err != nil {
+				t.Fatalf("FromImage() failed: %v", err)
+			}
+
+			if len(got) != img.Bounds().Dx()*img.Bounds().Dy() {
+				t.Fatalf("FromImage() returned %d pixels", len(got))
+			}
+			if i := firstDifference(got, want); i >= 0 {
+				t.Fatalf("pixel %d = %s, want %s", i, got[i], want[i])
+			}
+		})
+	}
+}
+
+func TestFromImagePalettedOutOfRange(t *testing.T) {
+	p := gocolor.Palette{gocolor.Black, gocolor.White}
+	img := image.NewPaletted(image.Rect(0, 0, 2, 1), p)
+	img.Pix = []uint8{1, 5}
+
+	got, err := FromImage(img)()
+	if err != nil {
+		t.Fatalf("FromImage() failed: %v", err)
+	}
+
+	want := []color.ARGB{0xFFFFFFFF, 0}
+	if !slices.Equal(got, want) {
+		t.Errorf("FromImage() = %v, want %v", got, want)
+	}
+}
+
+func firstDifference(a, b []color.ARGB) int {
+	for i := range min(len(a), len(b)) {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) != len(b) {
+		return min(len(a), len(b))
+	}
+	return -1
+}
+
+func TestFromImageWithSampleStride(t *testing.T) {
+	img := randomImages(64, 48)["nrgba"].(*image.NRGBA)
+	sub := img.SubImage(image.Rect(1, 2, 63, 47))
+
+	got, err := FromImage(sub, WithSampleStride(4))()
+	if err != nil {
+		t.Fatalf("FromImage() failed: %v", err)
+	}
+
+	var want []color.ARGB
+	for y := 2; y < 47; y += 4 {
+		for x := 1; x < 63; x += 4 {
+			want = append(want, color.ARGBFromInterface(img.At(x, y)))
+		}
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("FromImage() returned %d pixels, want %d", len(got), len(want))
+	}
+}
+
+func TestFromImageWithMaxPixels(t *testing.T) {
+	for name, img := range randomImages(640, 480) {
+		t.Run(name, func(t *testing.T) {
+			for _, n := range []int{1, 100, 1000, 640 * 480} {
+				got, err := FromImage(img, WithMaxPixels(n))()
+				if err != nil {
+					t.Fatalf("FromImage() failed: %v", err)
+				}
+				if len(got) == 0 || len(got) > n {
+					t.Errorf("WithMaxPixels(%d) returned %d pixels", n, len(got))
+				}
+
+				again, _ := FromImage(img, WithMaxPixels(n), WithSampleStride(3))()
+				if len(again) == 0 || len(again) > n {
+					t.Errorf("WithMaxPixels(%d) with stride returned %d pixels",
+						n, len(again))
+				}
+			}
+		})
+	}
+}
+
+func TestFromImageWithMaxPixelsAverage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	left := gocolor.RGBA{R: 200, G: 100, B: 0, A: 255}
+	right := gocolor.RGBA{R: 0, G: 50, B: 101, A: 255}
+	for y := range 2 {
+		img.SetRGBA(0, y, left)
+		img.SetRGBA(1, y, left)
+		img.SetRGBA(2, y, right)
+		img.SetRGBA(3, y, right)
+	}
+
+	got, err := FromImage(img, WithMaxPixels(2))()
+	if err != nil {
+		t.Fatalf("FromImage() failed: %v", err)
+	}
+
+	want := []color.ARGB{
+		color.ARGBFromInterface(left),
+		color.ARGBFromInterface(right),
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("FromImage() = %v, want %v", got, want)
+	}
+
+	got, _ = FromImage(img, WithMaxPixels(1))()
+	if want := color.NewARGB(255, 100, 75, 51); len(got) != 1 || got[0] != want {
+		t.Errorf("FromImage() = %v, want [%s]", got, want)
+	}
+}
+
+func TestFromImageWithAlphaThreshold(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	opaque := gocolor.NRGBA{R: 200, G: 100, B: 0, A: 255}
+	for y := range 2 {
+		img.SetNRGBA(0, y, opaque)
+		img.SetNRGBA(1, y, opaque)
+		img.SetNRGBA(2, y, gocolor.NRGBA{R: 255, G: 255, B: 255, A: 10})
+		img.SetNRGBA(3, y, gocolor.NRGBA{})
+	}
+
+	got, err := FromImage(img, WithAlphaThreshold(128))()
+	if err != nil {
+		t.Fatalf("FromImage() failed: %v", err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("FromImage() returned %d pixels, want 4", len(got))
+	}
+	for _, c := range got {
+		if c != color.ARGBFromInterface(opaque) {
+			t.Errorf("FromImage() returned %s, want only %s",
+				c, color.ARGBFromInterface(opaque))
+		}
+	}
+
+	got, _ = FromImage(img, WithAlphaThreshold(128), WithMaxPixels(4))()
+	want := []color.ARGB{color.ARGBFromInterface(opaque)}
+	if !slices.Equal(got, want) {
+		t.Errorf("FromImage() with max pixels = %v, want %v", got, want)
+	}
+}
+
+func TestFromImageReader(t *testing.T) {
+	images := randomImages(16, 8)
+	encoders := map[string]func(io.Writer, image.Image) error{
+		"png": png.Encode,
+		"jpeg": func(w io.Writer, img image.Image) error {
+			return jpeg.Encode(w, img, nil)
+		},
+		"gif": func(w io.Writer, img image.Image) error {
+			return gif.Encode(w, img, nil)
+		},
+	}
+
+	for name, encode := range encoders {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := encode(&buf, images["rgba"]); err != nil {
+				t.Fatalf("failed to encode image: %v", err)
+			}
+			img, _, err := image.Decode(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("failed to decode image: %v", err)
+			}
+
+			got, err := FromImageReader(&buf)()
+			if err != nil {
+				t.Fatalf("FromImageReader() failed: %v", err)
+			}
+			want, _ := FromImage(img)()
+			if !slices.Equal(got, want) {
+				t.Errorf("FromImageReader() differs from FromImage()")
+			}
+		})
+	}
+
+	_, err := FromImageReader(strings.NewReader("not an image"))()
+	if !errors.Is(err, image.ErrFormat) {
+		t.Errorf("FromImageReader() error = %v, want %v", err, image.ErrFormat)
+	}
+}
+
+func TestFromImageFile(t *testing.T) {
+	got, err := FromImageFile("quantizer/gophar.jpg", WithMaxPixels(100))()
+	if err != nil {
+		t.Fatalf("FromImageFile() failed: %v", err)
+	}
+	if len(got) == 0 || len(got) > 100 {
+		t.Errorf("FromImageFile() returned %d pixels", len(got))
+	}
+
+	_, err = FromImageFile("quantizer/missing.jpg")()
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("FromImageFile() error = %v, want %v", err, os.ErrNotExist)
+	}
+
+	_, err = FromImageFile("image.go")()
+	if !errors.Is(err, image.ErrFormat) {
+		t.Errorf("FromImageFile() error = %v, want %v", err, image.ErrFormat)
+	}
+}
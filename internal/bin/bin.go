@@ -0,0 +1,18 @@
+// Package bin implements the big endian float encoding shared by the binary
+// encodings of schemes and palettes.
+package bin
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// AppendFloat64 appends f to b as big endian float64.
+func AppendFloat64(b []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(b, math.Float64bits(f))
+}
+
+// Float64 returns the big endian float64 at the start of b.
+func Float64(b []byte) float64 {
+	return math.Float64frombits(binary.BigEndian.Uint64(b))
+}
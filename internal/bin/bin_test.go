@@ -0,0 +1,18 @@
+package bin
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloat64(t *testing.T) {
+	for _, f := range []float64{0, -1.5, 282.7, math.Inf(1)} {
+		b := AppendFloat64([]byte{0xFF}, f)
+		if len(b) != 9 {
+			t.Fatalf("AppendFloat64() appended %d bytes, want 8", len(b)-1)
+		}
+		if got := Float64(b[1:]); got != f {
+			t.Errorf("Float64() = %v, want %v", got, f)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Package strcase converts role names between the cases used by the exported
+// formats.
+package strcase
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Camel converts snake case name to lower camel case.
+func Camel(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = Capitalize(parts[i])
+	}
+	return strings.Join(parts, "")
+}
+
+// Snake converts camel case name to lower snake case.
+func Snake(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// Capitalize returns s with its first letter in upper case.
+func Capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
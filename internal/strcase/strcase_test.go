@@ -0,0 +1,25 @@
+package strcase
+
+import "testing"
+
+func TestCase(t *testing.T) {
+	tests := []struct {
+		snake string
+		camel string
+	}{
+		{"primary", "primary"},
+		{"on_primary_container", "onPrimaryContainer"},
+		{"surface_container_high", "surfaceContainerHigh"},
+	}
+	for _, tc := range tests {
+		if got := Camel(tc.snake); got != tc.camel {
+			t.Errorf("Camel(%q) = %q, want %q", tc.snake, got, tc.camel)
+		}
+		if got := Snake(tc.camel); got != tc.snake {
+			t.Errorf("Snake(%q) = %q, want %q", tc.camel, got, tc.snake)
+		}
+	}
+	if got := Capitalize("dark"); got != "Dark" {
+		t.Errorf("Capitalize(%q) = %q, want %q", "dark", got, "Dark")
+	}
+}
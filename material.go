@@ -2,8 +2,9 @@ package material
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
-	"image"
+	"fmt"
 	gocolor "image/color"
 	"io"
 	"maps"
@@ -13,85 +14,195 @@ import (
 	"github.com/Nadim147c/material/v3/blend"
 	"github.com/Nadim147c/material/v3/color"
 	"github.com/Nadim147c/material/v3/dynamic"
+	"github.com/Nadim147c/material/v3/palettes"
 	"github.com/Nadim147c/material/v3/quantizer"
 	"github.com/Nadim147c/material/v3/score"
 )
 
 // Colors is generated material you colors
 type Colors struct {
-	m      map[string]color.ARGB
 	Scheme *dynamic.Scheme `json:"scheme,omitzero"`
 
+	// Candidates is the ranked candidates for the source color.
+	Candidates []Candidate `json:"candidates,omitempty"`
+
 	CustomColors map[string]CustomColor `json:"custom"`
 
-	Background              color.ARGB `json:"background"`
-	Error                   color.ARGB `json:"error"`
-	ErrorContainer          color.ARGB `json:"error_container"`
-	ErrorDim                color.ARGB `json:"error_dim"`
-	InverseOnSurface        color.ARGB `json:"inverse_on_surface"`
-	InversePrimary          color.ARGB `json:"inverse_primary"`
-	InverseSurface          color.ARGB `json:"inverse_surface"`
-	OnBackground            color.ARGB `json:"on_background"`
-	OnError                 color.ARGB `json:"on_error"`
-	OnErrorContainer        color.ARGB `json:"on_error_container"`
-	OnPrimary               color.ARGB `json:"on_primary"`
-	OnPrimaryContainer      color.ARGB `json:"on_primary_container"`
-	OnPrimaryFixed          color.ARGB `json:"on_primary_fixed"`
-	OnPrimaryFixedVariant   color.ARGB `json:"on_primary_fixed_variant"`
-	OnSecondary             color.ARGB `json:"on_secondary"`
-	OnSecondaryContainer    color.ARGB `json:"on_secondary_container"`
-	OnSecondaryFixed        color.ARGB `json:"on_secondary_fixed"`
-	OnSecondaryFixedVariant color.ARGB `json:"on_secondary_fixed_variant"`
-	OnSurface               color.ARGB `json:"on_surface"`
-	OnSurfaceVariant        color.ARGB `json:"on_surface_variant"`
-	OnTertiary              color.ARGB `json:"on_tertiary"`
-	OnTertiaryContainer     color.ARGB `json:"on_tertiary_container"`
-	OnTertiaryFixed         color.ARGB `json:"on_tertiary_fixed"`
-	OnTertiaryFixedVariant  color.ARGB `json:"on_tertiary_fixed_variant"`
-	Outline                 color.ARGB `json:"outline"`
-	OutlineVariant          color.ARGB `json:"outline_variant"`
-	Primary                 color.ARGB `json:"primary"`
-	PrimaryContainer        color.ARGB `json:"primary_container"`
-	PrimaryDim              color.ARGB `json:"primary_dim"`
-	PrimaryFixed            color.ARGB `json:"primary_fixed"`
-	PrimaryFixedDim         color.ARGB `json:"primary_fixed_dim"`
-	Scrim                   color.ARGB `json:"scrim"`
-	Secondary               color.ARGB `json:"secondary"`
-	SecondaryContainer      color.ARGB `json:"secondary_container"`
-	SecondaryDim            color.ARGB `json:"secondary_dim"`
-	SecondaryFixed          color.ARGB `json:"secondary_fixed"`
-	SecondaryFixedDim       color.ARGB `json:"secondary_fixed_dim"`
-	Shadow                  color.ARGB `json:"shadow"`
-	Surface                 color.ARGB `json:"surface"`
-	SurfaceBright           color.ARGB `json:"surface_bright"`
-	SurfaceContainer        color.ARGB `json:"surface_container"`
-	SurfaceContainerHigh    color.ARGB `json:"surface_container_high"`
-	SurfaceContainerHighest color.ARGB `json:"surface_container_highest"`
-	SurfaceContainerLow     color.ARGB `json:"surface_container_low"`
-	SurfaceContainerLowest  color.ARGB `json:"surface_container_lowest"`
-	SurfaceDim              color.ARGB `json:"surface_dim"`
-	SurfaceTint             color.ARGB `json:"surface_tint"`
-	SurfaceVariant          color.ARGB `json:"surface_variant"`
-	Tertiary                color.ARGB `json:"tertiary"`
-	TertiaryContainer       color.ARGB `json:"tertiary_container"`
-	TertiaryDim             color.ARGB `json:"tertiary_dim"`
-	TertiaryFixed           color.ARGB `json:"tertiary_fixed"`
-	TertiaryFixedDim        color.ARGB `json:"tertiary_fixed_dim"`
-}
-
-// Map returns map with color name in snake case as name and color.ARGB as value
+	// Extra is the additional roles registered with WithColor by role name.
+	Extra map[string]color.ARGB `json:"extra,omitempty"`
+
+	Background                    color.ARGB `json:"background"`
+	Error                         color.ARGB `json:"error"`
+	ErrorContainer                color.ARGB `json:"error_container"`
+	ErrorDim                      color.ARGB `json:"error_dim"`
+	InverseOnSurface              color.ARGB `json:"inverse_on_surface"`
+	InversePrimary                color.ARGB `json:"inverse_primary"`
+	InverseSurface                color.ARGB `json:"inverse_surface"`
+	NeutralPaletteKeyColor        color.ARGB `json:"neutral_palette_key_color"`
+	NeutralVariantPaletteKeyColor color.ARGB `json:"neutral_variant_palette_key_color"`
+	OnBackground                  color.ARGB `json:"on_background"`
+	OnError                       color.ARGB `json:"on_error"`
+	OnErrorContainer              color.ARGB `json:"on_error_container"`
+	OnPrimary                     color.ARGB `json:"on_primary"`
+	OnPrimaryContainer            color.ARGB `json:"on_primary_container"`
+	OnPrimaryFixed                color.ARGB `json:"on_primary_fixed"`
+	OnPrimaryFixedVariant         color.ARGB `json:"on_primary_fixed_variant"`
+	OnSecondary                   color.ARGB `json:"on_secondary"`
+	OnSecondaryContainer          color.ARGB `json:"on_secondary_container"`
+	OnSecondaryFixed              color.ARGB `json:"on_secondary_fixed"`
+	OnSecondaryFixedVariant       color.ARGB `json:"on_secondary_fixed_variant"`
+	OnSurface                     color.ARGB `json:"on_surface"`
+	OnSurfaceVariant              color.ARGB `json:"on_surface_variant"`
+	OnTertiary                    color.ARGB `json:"on_tertiary"`
+	OnTertiaryContainer           color.ARGB `json:"on_tertiary_container"`
+	OnTertiaryFixed               color.ARGB `json:"on_tertiary_fixed"`
+	OnTertiaryFixedVariant        color.ARGB `json:"on_tertiary_fixed_variant"`
+	Outline                       color.ARGB `json:"outline"`
+	OutlineVariant                color.ARGB `json:"outline_variant"`
+	Primary                       color.ARGB `json:"primary"`
+	PrimaryContainer              color.ARGB `json:"primary_container"`
+	PrimaryDim                    color.ARGB `json:"primary_dim"`
+	PrimaryFixed                  color.ARGB `json:"primary_fixed"`
+	PrimaryFixedDim               color.ARGB `json:"primary_fixed_dim"`
+	PrimaryPaletteKeyColor        color.ARGB `json:"primary_palette_key_color"`
+	Scrim                         color.ARGB `json:"scrim"`
+	Secondary                     color.ARGB `json:"secondary"`
+	SecondaryContainer            color.ARGB `json:"secondary_container"`
+	SecondaryDim                  color.ARGB `json:"secondary_dim"`
+	SecondaryFixed                color.ARGB `json:"secondary_fixed"`
+	SecondaryFixedDim             color.ARGB `json:"secondary_fixed_dim"`
+	SecondaryPaletteKeyColor      color.ARGB `json:"secondary_palette_key_color"`
+	Shadow                        color.ARGB `json:"shadow"`
+	Surface                       color.ARGB `json:"surface"`
+	SurfaceBright                 color.ARGB `json:"surface_bright"`
+	SurfaceContainer              color.ARGB `json:"surface_container"`
+	SurfaceContainerHigh          color.ARGB `json:"surface_container_high"`
+	SurfaceContainerHighest       color.ARGB `json:"surface_container_highest"`
+	SurfaceContainerLow           color.ARGB `json:"surface_container_low"`
+	SurfaceContainerLowest        color.ARGB `json:"surface_container_lowest"`
+	SurfaceDim                    color.ARGB `json:"surface_dim"`
+	SurfaceTint                   color.ARGB `json:"surface_tint"`
+	SurfaceVariant                color.ARGB `json:"surface_variant"`
+	Tertiary                      color.ARGB `json:"tertiary"`
+	TertiaryContainer             color.ARGB `json:"tertiary_container"`
+	TertiaryDim                   color.ARGB `json:"tertiary_dim"`
+	TertiaryFixed                 color.ARGB `json:"tertiary_fixed"`
+	TertiaryFixedDim              color.ARGB `json:"tertiary_fixed_dim"`
+	TertiaryPaletteKeyColor       color.ARGB `json:"tertiary_palette_key_color"`
+}
+
+// Map returns map with color name in snake case as name and color.ARGB as value.
+// Custom colors are included as name, on_name, name_container,
+// on_name_container and so on, where name is the lower case name of the custom
+// color. Roles registered with WithColor are included by their name. Roles
+// which are not available in the scheme version are omitted. Custom roles never
+// replace other roles of the same name, such custom colors are rejected by
+// Generate.
 func (c *Colors) Map() map[string]color.ARGB {
-	m := maps.Clone(c.m)
-	for k, v := range c.CustomColors {
-		key := strings.ToLower(k)
-		m[key] = v.Color
-		m["on_"+key] = v.Color
+	m := map[string]color.ARGB{}
+	for name, argb := range c.roles() {
+		if *argb != 0 {
+			m[name] = *argb
+		}
+	}
+	maps.Copy(m, c.Extra)
+	for name, custom := range c.CustomColors {
+		for role, argb := range custom.roles(strings.ToLower(name)) {
+			if _, ok := m[role]; !ok && *argb != 0 {
+				m[role] = *argb
+			}
+		}
 	}
 	return m
 }
 
-// createColors converts a map of color names to Color pointers into a Colors
-// struct
+// UnmarshalJSON decodes colors encoded by json.Marshal. The colors are not
+// regenerated. Roles which are not available in the version of the scheme are
+// reset to zero.
+func (c *Colors) UnmarshalJSON(data []byte) error {
+	type colors Colors // colors doesn't have the UnmarshalJSON method
+	var v colors
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*c = Colors(v)
+
+	if c.Scheme != nil {
+		m := c.Scheme.ToColorMap()
+		for name, argb := range c.roles() {
+			if m[name] == nil {
+				*argb = 0
+			}
+		}
+	}
+	return nil
+}
+
+// roles returns the scheme roles of c by snake case name
+func (c *Colors) roles() map[string]*color.ARGB {
+	return map[string]*color.ARGB{
+		"background":                        &c.Background,
+		"error":                             &c.Error,
+		"error_container":                   &c.ErrorContainer,
+		"error_dim":                         &c.ErrorDim,
+		"inverse_on_surface":                &c.InverseOnSurface,
+		"inverse_primary":                   &c.InversePrimary,
+		"inverse_surface":                   &c.InverseSurface,
+		"neutral_palette_key_color":         &c.NeutralPaletteKeyColor,
+		"neutral_variant_palette_key_color": &c.NeutralVariantPaletteKeyColor,
+		"on_background":                     &c.OnBackground,
+		"on_error":                          &c.OnError,
+		"on_error_container":                &c.OnErrorContainer,
+		"on_primary":                        &c.OnPrimary,
+		"on_primary_container":              &c.OnPrimaryContainer,
+		"on_primary_fixed":                  &c.OnPrimaryFixed,
+		"on_primary_fixed_variant":          &c.OnPrimaryFixedVariant,
+		"on_secondary":                      &c.OnSecondary,
+		"on_secondary_container":            &c.OnSecondaryContainer,
+		"on_secondary_fixed":                &c.OnSecondaryFixed,
+		"on_secondary_fixed_variant":        &c.OnSecondaryFixedVariant,
+		"on_surface":                        &c.OnSurface,
+		"on_surface_variant":                &c.OnSurfaceVariant,
+		"on_tertiary":                       &c.OnTertiary,
+		"on_tertiary_container":             &c.OnTertiaryContainer,
+		"on_tertiary_fixed":                 &c.OnTertiaryFixed,
+		"on_tertiary_fixed_variant":         &c.OnTertiaryFixedVariant,
+		"outline":                           &c.Outline,
+		"outline_variant":                   &c.OutlineVariant,
+		"primary":                           &c.Primary,
+		"primary_container":                 &c.PrimaryContainer,
+		"primary_dim":                       &c.PrimaryDim,
+		"primary_fixed":                     &c.PrimaryFixed,
+		"primary_fixed_dim":                 &c.PrimaryFixedDim,
+		"primary_palette_key_color":         &c.PrimaryPaletteKeyColor,
+		"scrim":                             &c.Scrim,
+		"secondary":                         &c.Secondary,
+		"secondary_container":               &c.SecondaryContainer,
+		"secondary_dim":                     &c.SecondaryDim,
+		"secondary_fixed":                   &c.SecondaryFixed,
+		"secondary_fixed_dim":               &c.SecondaryFixedDim,
+		"secondary_palette_key_color":       &c.SecondaryPaletteKeyColor,
+		"shadow":                            &c.Shadow,
+		"surface":                           &c.Surface,
+		"surface_bright":                    &c.SurfaceBright,
+		"surface_container":                 &c.SurfaceContainer,
+		"surface_container_high":            &c.SurfaceContainerHigh,
+		"surface_container_highest":         &c.SurfaceContainerHighest,
+		"surface_container_low":             &c.SurfaceContainerLow,
+		"surface_container_lowest":          &c.SurfaceContainerLowest,
+		"surface_dim":                       &c.SurfaceDim,
+		"surface_tint":                      &c.SurfaceTint,
+		"surface_variant":                   &c.SurfaceVariant,
+		"tertiary":                          &c.Tertiary,
+		"tertiary_container":                &c.TertiaryContainer,
+		"tertiary_dim":                      &c.TertiaryDim,
+		"tertiary_fixed":                    &c.TertiaryFixed,
+		"tertiary_fixed_dim":                &c.TertiaryFixedDim,
+		"tertiary_palette_key_color":        &c.TertiaryPaletteKeyColor,
+	}
+}
+
+// createColors resolves the roles of scheme and the custom colors
 func createColors(
 	scheme *dynamic.Scheme,
 	custom map[string]CustomColorOption,
@@ -101,108 +212,82 @@ func createColors(
 
 	customColors := make(map[string]CustomColor, len(custom))
 	for name, opt := range custom {
-		customColors[name] = createCustomColor(opt, scheme.Dark, primary)
-	}
-
-	return &Colors{
-		Scheme:                  scheme,
-		Primary:                 primary,
-		CustomColors:            customColors,
-		Background:              calc(scheme, m["background"]),
-		Error:                   calc(scheme, m["error"]),
-		ErrorContainer:          calc(scheme, m["error_container"]),
-		ErrorDim:                calc(scheme, m["error_dim"]),
-		InverseOnSurface:        calc(scheme, m["inverse_on_surface"]),
-		InversePrimary:          calc(scheme, m["inverse_primary"]),
-		InverseSurface:          calc(scheme, m["inverse_surface"]),
-		OnBackground:            calc(scheme, m["on_background"]),
-		OnError:                 calc(scheme, m["on_error"]),
-		OnErrorContainer:        calc(scheme, m["on_error_container"]),
-		OnPrimary:               calc(scheme, m["on_primary"]),
-		OnPrimaryContainer:      calc(scheme, m["on_primary_container"]),
-		OnPrimaryFixed:          calc(scheme, m["on_primary_fixed"]),
-		OnPrimaryFixedVariant:   calc(scheme, m["on_primary_fixed_variant"]),
-		OnSecondary:             calc(scheme, m["on_secondary"]),
-		OnSecondaryContainer:    calc(scheme, m["on_secondary_container"]),
-		OnSecondaryFixed:        calc(scheme, m["on_secondary_fixed"]),
-		OnSecondaryFixedVariant: calc(scheme, m["on_secondary_fixed_variant"]),
-		OnSurface:               calc(scheme, m["on_surface"]),
-		OnSurfaceVariant:        calc(scheme, m["on_surface_variant"]),
-		OnTertiary:              calc(scheme, m["on_tertiary"]),
-		OnTertiaryContainer:     calc(scheme, m["on_tertiary_container"]),
-		OnTertiaryFixed:         calc(scheme, m["on_tertiary_fixed"]),
-		OnTertiaryFixedVariant:  calc(scheme, m["on_tertiary_fixed_variant"]),
-		Outline:                 calc(scheme, m["outline"]),
-		OutlineVariant:          calc(scheme, m["outline_variant"]),
-		PrimaryContainer:        calc(scheme, m["primary_container"]),
-		PrimaryDim:              calc(scheme, m["primary_dim"]),
-		PrimaryFixed:            calc(scheme, m["primary_fixed"]),
-		PrimaryFixedDim:         calc(scheme, m["primary_fixed_dim"]),
-		Scrim:                   calc(scheme, m["scrim"]),
-		Secondary:               calc(scheme, m["secondary"]),
-		SecondaryContainer:      calc(scheme, m["secondary_container"]),
-		SecondaryDim:            calc(scheme, m["secondary_dim"]),
-		SecondaryFixed:          calc(scheme, m["secondary_fixed"]),
-		SecondaryFixedDim:       calc(scheme, m["secondary_fixed_dim"]),
-		Shadow:                  calc(scheme, m["shadow"]),
-		Surface:                 calc(scheme, m["surface"]),
-		SurfaceBright:           calc(scheme, m["surface_bright"]),
-		SurfaceContainer:        calc(scheme, m["surface_container"]),
-		SurfaceContainerHigh:    calc(scheme, m["surface_container_high"]),
-		SurfaceContainerHighest: calc(scheme, m["surface_container_highest"]),
-		SurfaceContainerLow:     calc(scheme, m["surface_container_low"]),
-		SurfaceContainerLowest:  calc(scheme, m["surface_container_lowest"]),
-		SurfaceDim:              calc(scheme, m["surface_dim"]),
-		SurfaceTint:             calc(scheme, m["surface_tint"]),
-		SurfaceVariant:          calc(scheme, m["surface_variant"]),
-		Tertiary:                calc(scheme, m["tertiary"]),
-		TertiaryContainer:       calc(scheme, m["tertiary_container"]),
-		TertiaryDim:             calc(scheme, m["tertiary_dim"]),
-		TertiaryFixed:           calc(scheme, m["tertiary_fixed"]),
-		TertiaryFixedDim:        calc(scheme, m["tertiary_fixed_dim"]),
+		customColors[name] = createCustomColor(name, opt, scheme, primary)
+	}
+
+	colors := &Colors{Scheme: scheme, CustomColors: customColors}
+	roles := colors.roles()
+	for name, argb := range roles {
+		*argb = calc(scheme, m[name])
+	}
+	for name, dc := range m {
+		if _, ok := roles[name]; ok || dc == nil {
+			continue
+		}
+		if colors.Extra == nil {
+			colors.Extra = map[string]color.ARGB{}
+		}
+		colors.Extra[name] = calc(scheme, dc)
 	}
+	return colors
 }
 
 // CustomColor is the custom colors generated from user defined colors
 type CustomColor struct {
-	Color            color.ARGB `json:"color"`
-	OnColor          color.ARGB `json:"on_color"`
-	ColorContainer   color.ARGB `json:"color_container"`
-	OnColorContainer color.ARGB `json:"on_color_container"`
+	Color               color.ARGB `json:"color"`
+	OnColor             color.ARGB `json:"on_color"`
+	ColorContainer      color.ARGB `json:"color_container"`
+	OnColorContainer    color.ARGB `json:"on_color_container"`
+	ColorFixed          color.ARGB `json:"color_fixed"`
+	ColorFixedDim       color.ARGB `json:"color_fixed_dim"`
+	OnColorFixed        color.ARGB `json:"on_color_fixed"`
+	OnColorFixedVariant color.ARGB `json:"on_color_fixed_variant"`
+	// ColorDim is only available in 2025 spec.
+	ColorDim color.ARGB `json:"color_dim,omitzero"`
 }
 
-func tonedHctToARGB(hct color.Hct, tone float64) color.ARGB {
-	hct.Tone = tone
-	return hct.ToARGB()
+// roles returns the roles of c by the snake case role name of the custom color
+// named name
+func (c *CustomColor) roles(name string) map[string]*color.ARGB {
+	return map[string]*color.ARGB{
+		name:                            &c.Color,
+		"on_" + name:                    &c.OnColor,
+		name + "_container":             &c.ColorContainer,
+		"on_" + name + "_container":     &c.OnColorContainer,
+		name + "_fixed":                 &c.ColorFixed,
+		name + "_fixed_dim":             &c.ColorFixedDim,
+		"on_" + name + "_fixed":         &c.OnColorFixed,
+		"on_" + name + "_fixed_variant": &c.OnColorFixedVariant,
+		name + "_dim":                   &c.ColorDim,
+	}
 }
 
+// createCustomColor resolves the roles of the custom color named name. The
+// roles are resolved like the tertiary roles of scheme, see
+// dynamic.CustomColor. Blended colors are blended with primary.
 func createCustomColor(
+	name string,
 	option CustomColorOption,
-	dark bool,
-	to color.ARGB,
+	scheme *dynamic.Scheme,
+	primary color.ARGB,
 ) CustomColor {
 	var hct color.Hct
 
 	if option.Blend {
-		hct = blend.HctHueDirect(option.Color, to, option.Ratio)
+		hct = blend.HctHueDirect(option.Color, primary, option.Ratio)
 	} else {
 		hct = option.Color.ToHct()
 	}
 
-	if dark {
-		return CustomColor{
-			Color:            tonedHctToARGB(hct, 40),
-			OnColor:          tonedHctToARGB(hct, 100),
-			ColorContainer:   tonedHctToARGB(hct, 90),
-			OnColorContainer: tonedHctToARGB(hct, 10),
-		}
-	}
-	return CustomColor{
-		Color:            tonedHctToARGB(hct, 80),
-		OnColor:          tonedHctToARGB(hct, 20),
-		ColorContainer:   tonedHctToARGB(hct, 30),
-		OnColorContainer: tonedHctToARGB(hct, 90),
+	key := strings.ToLower(name)
+	roles := dynamic.NewCustomColor(key, *palettes.NewFromHct(hct)).
+		Roles(scheme.MaterialColor)
+
+	var cc CustomColor
+	for role, argb := range cc.roles(key) {
+		*argb = calc(scheme, roles[role])
 	}
+	return cc
 }
 
 // calc converts a *Color pointer to color.ARGB Returns 0 if the pointer is nil
@@ -216,22 +301,6 @@ func calc(s *dynamic.Scheme, c *dynamic.Color) color.ARGB {
 // Source is a function that returns source colors for material you
 type Source func() ([]color.ARGB, error)
 
-// FromImage returns Source colors from image.Image interface
-func FromImage(img image.Image) Source {
-	return func() ([]color.ARGB, error) {
-		bounds := img.Bounds()
-		pixels := make([]color.ARGB, 0, bounds.Dx()*bounds.Dy())
-		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-			for x := bounds.Min.X; x < bounds.Max.X; x++ {
-				c := img.At(x, y)
-				argb := color.ARGBFromInterface(c)
-				pixels = append(pixels, argb)
-			}
-		}
-		return pixels, nil
-	}
-}
-
 // FromColor returns a Source from a single color.Color interface
 func FromColor(c gocolor.Color) Source {
 	argb := color.ARGBFromInterface(c)
@@ -274,10 +343,11 @@ func FromARGB(argbs []color.ARGB) Source {
 }
 
 // FromReader returns Source colors from an io.Reader containing RGB bytes.
-// Reads until EOF and extracts RGB triplets from the stream.
+// Reads until EOF and extracts RGB triplets from the stream. Use FromStream
+// for long streams, which doesn't keep the stream in memory.
 //
 // WARNING: Do NOT pass image encoded file readers (e.g., PNG, JPEG). Use
-// FromImage with image.Decode for encoded image files.
+// FromImageReader or FromImageFile for encoded image files.
 func FromReader(r io.Reader) Source {
 	return func() ([]color.ARGB, error) {
 		b, err := io.ReadAll(r)
@@ -365,7 +435,40 @@ type Settings struct {
 	Variant  dynamic.Variant  `json:"variant"`
 	Version  dynamic.Version  `json:"version"`
 
-	Custom map[string]CustomColorOption `json:"-"`
+	// MaxColors is the max number of colors quantized from the source.
+	MaxColors int `json:"max_colors"`
+	// Candidate is the index of the ranked candidate used as source color.
+	Candidate int `json:"candidate"`
+	// AlphaWeighting weights source colors by alpha in the default quantizer
+	// so that transparent colors don't affect the source color.
+	AlphaWeighting bool `json:"alpha_weighting"`
+	// Seed makes the default quantizer reproducible. Nil uses the global
+	// random source. Custom quantizers have to be seeded themselves.
+	Seed *int64 `json:"seed,omitempty"`
+	// Workers is the max number of sources generated concurrently by
+	// GenerateBatch. Defaults to runtime.GOMAXPROCS.
+	Workers int `json:"workers"`
+
+	// Quantizer quantizes the source colors. Defaults to quantizer.Celebi.
+	Quantizer quantizer.Quantizer          `json:"-"`
+	Score     []score.Option               `json:"-"`
+	Custom    map[string]CustomColorOption `json:"-"`
+	Palettes  Palettes                     `json:"-"`
+	// Colors is the additional roles registered with the dynamic scheme.
+	Colors []*dynamic.Color `json:"-"`
+	// Spec replaces the material color spec of the scheme version.
+	Spec dynamic.MaterialColorSpec `json:"-"`
+}
+
+// Palettes overrides the tonal palettes of the dynamic scheme. Nil palettes
+// are derived from the source color.
+type Palettes struct {
+	Primary        *palettes.TonalPalette
+	Secondary      *palettes.TonalPalette
+	Tertiary       *palettes.TonalPalette
+	Neutral        *palettes.TonalPalette
+	NeutralVariant *palettes.TonalPalette
+	Error          *palettes.TonalPalette
 }
 
 // Option is a func modifes the dynamic scheme settings
@@ -401,7 +504,9 @@ func WithVersion(v dynamic.Version) Option {
 	return func(s *Settings) { s.Version = v }
 }
 
-// WithCustomColor returns an Option that adds a custom color.
+// WithCustomColor returns an Option that adds a custom color. Generating colors
+// fails if a role of the custom color has the name of another role, e.g. a
+// custom color named error.
 func WithCustomColor(name string, c gocolor.Color) Option {
 	return func(o *Settings) {
 		if o.Custom == nil {
@@ -414,7 +519,8 @@ func WithCustomColor(name string, c gocolor.Color) Option {
 }
 
 // WithCustomColorBlend returns an Option that adds a custom color which will be
-// blended with primary color by geven ratio. Ratio range [0, 1].
+// blended with primary color by geven ratio. Ratio range [0, 1]. The name is
+// checked like the name of WithCustomColor.
 func WithCustomColorBlend(name string, c gocolor.Color, ratio float64) Option {
 	return func(o *Settings) {
 		if o.Custom == nil {
@@ -428,12 +534,80 @@ func WithCustomColorBlend(name string, c gocolor.Color, ratio float64) Option {
 	}
 }
 
+// WithColor returns an Option that adds roles to the dynamic scheme, e.g.
+// success or warning roles of a design system. The roles are resolved like the
+// built-in roles and are available in Colors.Extra, Colors.Map and
+// Scheme.ToColorMap. Generating colors fails if roles have the same name, or
+// the name of a built-in or custom color role. See dynamic.Scheme.Register.
+func WithColor(colors ...*dynamic.Color) Option {
+	return func(s *Settings) { s.Colors = append(s.Colors, colors...) }
+}
+
+// WithSpec returns an Option that sets the material color spec used for the
+// roles of the dynamic scheme, e.g. a dynamic.DocumentSpec loaded with
+// dynamic.ReadSpec. The version of a dynamic.VersionedSpec, e.g. a
+// dynamic.DocumentSpec, replaces the version of the settings. Schemes decoded
+// from JSON or binary use the spec of their version instead.
+func WithSpec(spec dynamic.MaterialColorSpec) Option {
+	return func(s *Settings) { s.Spec = spec }
+}
+
+// WithMaxColors returns an Option that sets the max number of colors quantized
+// from the source
+func WithMaxColors(n int) Option {
+	return func(s *Settings) { s.MaxColors = n }
+}
+
+// WithQuantizer returns an Option that sets the quantizer used for quantizing
+// source colors
+func WithQuantizer(q quantizer.Quantizer) Option {
+	return func(s *Settings) { s.Quantizer = q }
+}
+
+// WithAlphaWeighting returns an Option that sets whether the default quantizer
+// weights source colors by alpha
+func WithAlphaWeighting(w bool) Option {
+	return func(s *Settings) { s.AlphaWeighting = w }
+}
+
+// WithSeed returns an Option that seeds the default quantizer, so that the same
+// source always generates the same colors
+func WithSeed(seed int64) Option {
+	return func(s *Settings) { s.Seed = &seed }
+}
+
+// WithWorkers returns an Option that sets the max number of sources generated
+// concurrently by GenerateBatch
+func WithWorkers(n int) Option {
+	return func(s *Settings) { s.Workers = n }
+}
+
+// WithScoreOptions returns an Option that sets the options used for scoring
+// quantized colors
+func WithScoreOptions(options ...score.Option) Option {
+	return func(s *Settings) { s.Score = options }
+}
+
+// WithCandidate returns an Option that sets the index of the ranked candidate
+// used as source color. Candidates are available in Colors.Candidates.
+func WithCandidate(i int) Option {
+	return func(s *Settings) { s.Candidate = i }
+}
+
+// WithPalettes returns an Option that overrides the tonal palettes
+func WithPalettes(p Palettes) Option {
+	return func(s *Settings) { s.Palettes = p }
+}
+
 // WithSettings settings all values of settings
 func WithSettings(s Settings) Option {
 	return func(o *Settings) { *o = s }
 }
 
-var errNoColorFound = errors.New("no source colors")
+var (
+	errNoColorFound = errors.New("no source colors")
+	errRoleExists   = errors.New("role already exists")
+)
 
 // Generate generates material you colors
 func Generate(src Source, options ...Option) (*Colors, error) {
@@ -442,16 +616,40 @@ func Generate(src Source, options ...Option) (*Colors, error) {
 		return nil, err
 	}
 
-	if len(colors) == 0 {
-		return nil, errors.New("no source colors")
+	return generate(colors, nil, newSettings(options))
+}
+
+// generate generates colors from source colors and their weights. Colors are
+// not weighted if weights is nil.
+func generate(
+	colors []color.ARGB,
+	weights []float64,
+	cfg *Settings,
+) (*Colors, error) {
+	source, candidates, err := sourceColor(cfg, colors, weights)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme, err := newScheme(source, cfg)
+	if err != nil {
+		return nil, err
 	}
 
+	result := createColors(scheme, cfg.Custom)
+	result.Candidates = candidates
+	return result, nil
+}
+
+// newSettings returns default settings modified by options
+func newSettings(options []Option) *Settings {
 	cfg := &Settings{
-		Contrast: 0,
-		Dark:     false,
-		Variant:  VariantExpressive,
-		Version:  Version2025,
-		Platform: PlatformPhone,
+		Contrast:  0,
+		Dark:      false,
+		Variant:   VariantExpressive,
+		Version:   Version2025,
+		Platform:  PlatformPhone,
+		MaxColors: defaultMaxColors,
 	}
 	for opt := range slices.Values(options) {
 		opt(cfg)
@@ -460,25 +658,15 @@ func Generate(src Source, options ...Option) (*Colors, error) {
 	if cfg.Context == nil {
 		cfg.Context = context.Background()
 	}
+	return cfg
+}
 
-	source := colors[0]
-
-	if len(colors) != 1 {
-		quantized, err := quantizer.QuantizeCelebiContext(
-			cfg.Context, colors, 5,
-		)
-		if err != nil {
-			return nil, err
-		}
-		if len(quantized) == 0 {
-			return nil, errNoColorFound
-		}
-
-		scored := score.Score(quantized)
-		if len(scored) == 0 {
-			return nil, errNoColorFound
-		}
-		source = scored[0]
+// newScheme creates a dynamic scheme from source color using cfg, and registers
+// the additional roles of cfg with the spec of cfg
+func newScheme(source color.ARGB, cfg *Settings) (*dynamic.Scheme, error) {
+	version := cfg.Version
+	if spec, ok := cfg.Spec.(dynamic.VersionedSpec); ok {
+		version = spec.Version()
 	}
 
 	scheme := dynamic.NewDynamicScheme(
@@ -487,8 +675,46 @@ func Generate(src Source, options ...Option) (*Colors, error) {
 		cfg.Contrast,
 		cfg.Dark,
 		cfg.Platform,
-		cfg.Version,
+		version,
+		cfg.Palettes.Primary,
+		cfg.Palettes.Secondary,
+		cfg.Palettes.Tertiary,
+		cfg.Palettes.Neutral,
+		cfg.Palettes.NeutralVariant,
+		cfg.Palettes.Error,
 	)
+	if cfg.Spec != nil {
+		scheme.MaterialColor = cfg.Spec
+	}
+	if err := scheme.Register(cfg.Colors...); err != nil {
+		return nil, err
+	}
+	if err := checkCustomColors(scheme, cfg.Custom); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
 
-	return createColors(scheme, cfg.Custom), nil
+// checkCustomColors returns an error if a role of a custom color has the name
+// of a role of scheme or of another custom color, e.g. custom colors named
+// error, or a and a_fixed.
+func checkCustomColors(
+	scheme *dynamic.Scheme,
+	custom map[string]CustomColorOption,
+) error {
+	roles := scheme.ToColorMap()
+	for _, name := range slices.Sorted(maps.Keys(custom)) {
+		key := strings.ToLower(name)
+		cc := dynamic.NewCustomColor(key, palettes.TonalPalette{})
+		for role := range cc.Roles(scheme.MaterialColor) {
+			if _, ok := roles[role]; ok {
+				return fmt.Errorf(
+					"%w: role %q of custom color %q",
+					errRoleExists, role, name,
+				)
+			}
+			roles[role] = nil
+		}
+	}
+	return nil
 }
@@ -1,10 +1,24 @@
 package material
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
+	gocolor "image/color"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/contrast"
+	"github.com/Nadim147c/material/v3/dynamic"
+	"github.com/Nadim147c/material/v3/palettes"
+	"github.com/Nadim147c/material/v3/quantizer"
+	"github.com/Nadim147c/material/v3/score"
 )
 
 func ExampleGenerate() {
@@ -27,3 +41,401 @@ func TestGenerate(t *testing.T) {
 	}
 	t.Log(colors)
 }
+
+func TestGenerateCandidates(t *testing.T) {
+	pixels := []string{"#FF1100", "#11FF00", "#1111FF", "#3F3F11", "#007755"}
+
+	for i := range 3 {
+		colors, err := Generate(
+			FromHexes(pixels),
+			WithMaxColors(8),
+			WithScoreOptions(score.WithLimit(3)),
+			WithCandidate(i),
+		)
+		// Quantization may find less than 3 candidates
+		if i > 0 && errors.Is(err, errCandidateRange) {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("failed to generate colors: %v", err)
+		}
+
+		if len(colors.Candidates) > 3 {
+			t.Fatalf("got %d candidates, want at most 3",
+				len(colors.Candidates))
+		}
+		for _, c := range colors.Candidates {
+			if c.Population <= 0 {
+				t.Errorf("candidate %s has population %d", c.Color, c.Population)
+			}
+		}
+
+		source := colors.Scheme.SourceColorHct.ToARGB()
+		if source != colors.Candidates[i].Color {
+			t.Errorf("source color = %s, want candidate %d %s",
+				source, i, colors.Candidates[i].Color)
+		}
+	}
+
+	_, err := Generate(
+		FromHexes(pixels),
+		WithScoreOptions(score.WithLimit(3)),
+		WithCandidate(3),
+	)
+	if !errors.Is(err, errCandidateRange) {
+		t.Errorf("Generate() with out of range candidate returned %v", err)
+	}
+}
+
+func TestGenerateWithQuantizer(t *testing.T) {
+	pixels := []string{"#FF1100", "#11FF00", "#1111FF", "#3F3F11", "#007755"}
+	want := color.ARGBFromHexMust("#00AA88")
+
+	q := quantizer.Func(func(
+		_ context.Context,
+		_ []color.ARGB,
+		_ int,
+	) (quantizer.QuantizedMap, error) {
+		return quantizer.QuantizedMap{want: 10}, nil
+	})
+
+	colors, err := Generate(FromHexes(pixels), WithQuantizer(q))
+	if err != nil {
+		t.Fatalf("failed to generate colors: %v", err)
+	}
+	if got := colors.Scheme.SourceColorHct.ToARGB(); got != want {
+		t.Errorf("source color = %s, want %s", got, want)
+	}
+
+	first, err := Generate(FromHexes(pixels), WithQuantizer(quantizer.Wu{}))
+	if err != nil {
+		t.Fatalf("failed to generate colors: %v", err)
+	}
+	second, err := Generate(FromHexes(pixels), WithQuantizer(quantizer.Wu{}))
+	if err != nil {
+		t.Fatalf("failed to generate colors: %v", err)
+	}
+	if first.Primary != second.Primary {
+		t.Errorf("Wu quantizer is not deterministic: %s != %s",
+			first.Primary, second.Primary)
+	}
+}
+
+func TestGenerateWithSeed(t *testing.T) {
+	pixels := []string{"#FF1100", "#11FF00", "#1111FF", "#3F3F11", "#007755"}
+
+	want, err := Generate(FromHexes(pixels), WithSeed(7))
+	if err != nil {
+		t.Fatalf("failed to generate colors: %v", err)
+	}
+
+	for range 10 {
+		got, err := Generate(FromHexes(pixels), WithSeed(7))
+		if err != nil {
+			t.Fatalf("failed to generate colors: %v", err)
+		}
+		if !slices.Equal(got.Candidates, want.Candidates) ||
+			got.Primary != want.Primary {
+			t.Fatalf("seeded generation differs: %v != %v",
+				got.Candidates, want.Candidates)
+		}
+	}
+}
+
+func TestGenerateWithAlphaWeighting(t *testing.T) {
+	// An icon like image with a transparent background, a green logo and a
+	// translucent red shadow.
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := range 64 {
+		for x := range 64 {
+			switch {
+			case x >= 24 && x < 40 && y >= 24 && y < 40:
+				img.SetNRGBA(x, y, gocolor.NRGBA{G: 200, B: 50, A: 255})
+			case y >= 40 && y < 56:
+				img.SetNRGBA(x, y, gocolor.NRGBA{R: 220, A: 8})
+			}
+		}
+	}
+
+	colors, err := Generate(
+		FromImage(img),
+		WithAlphaWeighting(true),
+		WithSeed(1),
+	)
+	if err != nil {
+		t.Fatalf("failed to generate colors: %v", err)
+	}
+
+	want := color.ARGBFromInterface(gocolor.NRGBA{G: 200, B: 50, A: 255})
+	if got := colors.Candidates[0].Color; got != want {
+		t.Errorf("source color = %s, want %s", got, want)
+	}
+}
+
+func TestSchemeConcurrent(t *testing.T) {
+	source := color.ARGBFromHexMust("#0044FF")
+	cfg := newSettings(nil)
+	scheme, err := newScheme(source, cfg)
+	if err != nil {
+		t.Fatalf("failed to create scheme: %v", err)
+	}
+	want := createColors(scheme, nil)
+
+	scheme, _ = newScheme(source, cfg)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got := createColors(scheme, nil)
+			if got.Primary != want.Primary || got.Surface != want.Surface {
+				t.Errorf("concurrent colors differ: %s != %s",
+					got.Primary, want.Primary)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestColorsMap(t *testing.T) {
+	for _, version := range []dynamic.Version{Version2021, Version2025} {
+		colors, err := Generate(
+			FromHex("#0044FF"),
+			WithVersion(version),
+			WithCustomColor("Brand", color.ARGBFromHexMust("#FF8800")),
+		)
+		if err != nil {
+			t.Fatalf("failed to generate colors: %v", err)
+		}
+
+		m := colors.Map()
+		for name, dc := range colors.Scheme.ToColorMap() {
+			if _, ok := m[name]; ok != (dc != nil) {
+				t.Errorf("%s: role %q in map = %v, want %v",
+					version, name, ok, dc != nil)
+			}
+		}
+
+		brand := colors.CustomColors["Brand"]
+		want := map[string]color.ARGB{
+			"brand":              brand.Color,
+			"on_brand":           brand.OnColor,
+			"brand_container":    brand.ColorContainer,
+			"on_brand_container": brand.OnColorContainer,
+		}
+		for name, argb := range want {
+			if m[name] != argb {
+				t.Errorf("%s: %s = %s, want %s", version, name, m[name], argb)
+			}
+		}
+		if m["brand"] == m["on_brand"] {
+			t.Errorf("%s: brand and on_brand are both %s", version, m["brand"])
+		}
+
+		colors.CustomColors["error"] = brand
+		if got := colors.Map()["error"]; got != colors.Error {
+			t.Errorf("%s: custom error replaced error = %s, want %s",
+				version, got, colors.Error)
+		}
+	}
+}
+
+func TestColorsJSON(t *testing.T) {
+	for _, version := range []dynamic.Version{Version2021, Version2025} {
+		want, err := Generate(
+			FromHex("#0044FF"),
+			WithVersion(version),
+			WithDark(true),
+			WithContrast(0.5),
+			WithCustomColor("brand", color.ARGBFromHexMust("#FF8800")),
+		)
+		if err != nil {
+			t.Fatalf("failed to generate colors: %v", err)
+		}
+
+		data, err := json.Marshal(want)
+		if err != nil {
+			t.Fatalf("failed to marshal colors: %v", err)
+		}
+
+		var got Colors
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("failed to unmarshal colors: %v", err)
+		}
+
+		if !maps.Equal(got.Map(), want.Map()) {
+			t.Errorf("%s: map = %v, want %v", version, got.Map(), want.Map())
+		}
+		if got.Scheme == nil {
+			t.Fatalf("%s: scheme is nil", version)
+		}
+		if got.Scheme.Version != version || !got.Scheme.Dark ||
+			got.Scheme.Contrast != 0.5 {
+			t.Errorf("%s: scheme = %+v", version, got.Scheme)
+		}
+		primary := got.Scheme.ToColorMap()["primary"]
+		if p := primary.GetArgb(got.Scheme); p != want.Primary {
+			t.Errorf("%s: scheme primary = %s, want %s",
+				version, p, want.Primary)
+		}
+	}
+}
+
+func TestCustomColors(t *testing.T) {
+	for _, dark := range []bool{false, true} {
+		for _, level := range []float64{0, 1} {
+			colors, err := Generate(
+				FromHex("#0044FF"),
+				WithDark(dark),
+				WithContrast(level),
+				WithCustomColor("brand", color.ARGBFromHexMust("#FF8800")),
+			)
+			if err != nil {
+				t.Fatalf("failed to generate colors: %v", err)
+			}
+
+			brand := colors.CustomColors["brand"]
+			tone := brand.Color.ToHct().Tone
+			container := brand.ColorContainer.ToHct().Tone
+			if dark == (tone < container) {
+				t.Errorf("dark=%v: brand tone = %.1f, container tone = %.1f",
+					dark, tone, container)
+			}
+
+			ratio := contrast.RatioOfTones(tone, brand.OnColor.ToHct().Tone)
+			want := 4.5
+			if level == 1 {
+				want = 7
+			}
+			if ratio < want-0.1 {
+				t.Errorf("dark=%v contrast=%v: on_brand ratio = %.2f, want %v",
+					dark, level, ratio, want)
+			}
+			if brand.ColorDim == 0 || brand.ColorFixed == 0 {
+				t.Errorf("dark=%v: brand dim and fixed are not resolved", dark)
+			}
+		}
+	}
+}
+
+func TestCustomColorNames(t *testing.T) {
+	green := color.ARGBFromHexMust("#00FF00")
+	tests := map[string][]string{
+		"built-in":         {"error"},
+		"built-in surface": {"surface"},
+		"upper case":       {"Primary"},
+		"fixed":            {"a", "a_fixed"},
+		"same lower case":  {"brand", "Brand"},
+	}
+
+	for name, customs := range tests {
+		t.Run(name, func(t *testing.T) {
+			var options []Option
+			for _, c := range customs {
+				options = append(options, WithCustomColor(c, green))
+			}
+
+			if _, err := Generate(FromHex("#0044FF"), options...); err == nil {
+				t.Errorf("Generate() with custom colors %v returned nil error",
+					customs)
+			}
+			_, err := NewThemeBuilder(FromHex("#0044FF"), options...)
+			if err == nil {
+				t.Errorf("NewThemeBuilder() with custom colors %v returned nil "+
+					"error", customs)
+			}
+		})
+	}
+}
+
+func TestGenerateWithColor(t *testing.T) {
+	warning := &dynamic.Color{
+		Name: "warning",
+		Palette: func(*dynamic.Scheme) palettes.TonalPalette {
+			return *palettes.FromHueAndChroma(80, 60)
+		},
+		Tone: func(*dynamic.Scheme) float64 { return 50 },
+		Background: func(s *dynamic.Scheme) *dynamic.Color {
+			return s.MaterialColor.Surface()
+		},
+		ContrastCurve: func(*dynamic.Scheme) *dynamic.ContrastCurve {
+			return dynamic.NewContrastCurve(3, 4.5, 7, 11)
+		},
+	}
+
+	colors, err := Generate(FromHex("#0044FF"), WithColor(warning))
+	if err != nil {
+		t.Fatalf("failed to generate colors: %v", err)
+	}
+
+	want := warning.GetArgb(colors.Scheme)
+	if got := colors.Extra["warning"]; got != want {
+		t.Errorf("Extra[warning] = %s, want %s", got, want)
+	}
+
+	for _, options := range [][]Option{
+		{WithColor(warning, warning)},
+		{
+			WithColor(warning),
+			WithCustomColor("warning", color.ARGBFromHexMust("#FFAA00")),
+		},
+	} {
+		if _, err := Generate(FromHex("#0044FF"), options...); err == nil {
+			t.Error("Generate() with clashing roles returned nil error")
+		}
+	}
+	if got := colors.Map()["warning"]; got != want {
+		t.Errorf("Map()[warning] = %s, want %s", got, want)
+	}
+
+	_, err = Generate(FromHex("#0044FF"), WithColor(&dynamic.Color{
+		Name:    "primary",
+		Palette: warning.Palette,
+		Tone:    warning.Tone,
+	}))
+	if err == nil {
+		t.Errorf("Generate() with built-in role name succeeded")
+	}
+}
+
+func TestGenerateWithSpec(t *testing.T) {
+	spec, err := dynamic.ReadSpec(strings.NewReader(`{
+		"base": "2021",
+		"roles": {
+			"surface": {
+				"palette": "neutral",
+				"tone": {"light": 100, "dark": 0}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("failed to read spec: %v", err)
+	}
+
+	colors, err := Generate(FromHex("#0044FF"), WithSpec(spec))
+	if err != nil {
+		t.Fatalf("failed to generate colors: %v", err)
+	}
+	if colors.Scheme.Version != Version2021 {
+		t.Errorf("version = %s, want %s", colors.Scheme.Version, Version2021)
+	}
+	if colors.Surface != 0xFFFFFFFF {
+		t.Errorf("surface = %s, want #FFFFFF", colors.Surface)
+	}
+
+	// The version of any versioned spec is used, e.g. a wrapped spec
+	type wrapped struct{ *dynamic.DocumentSpec }
+	for _, s := range []dynamic.MaterialColorSpec{
+		wrapped{spec},
+		dynamic.MaterialSpec2021{},
+	} {
+		colors, err := Generate(FromHex("#0044FF"), WithSpec(s))
+		if err != nil {
+			t.Fatalf("failed to generate colors: %v", err)
+		}
+		if colors.Scheme.Version != Version2021 {
+			t.Errorf("%T: version = %s, want %s",
+				s, colors.Scheme.Version, Version2021)
+		}
+	}
+}
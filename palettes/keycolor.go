@@ -2,18 +2,21 @@ package palettes
 
 import (
 	"math"
+	"sync"
 
 	"github.com/Nadim147c/material/v3/color"
 )
 
-// KeyColor is a color that represents the hue and chroma of a tonal palette
+// KeyColor is a color that represents the hue and chroma of a tonal palette.
+// KeyColor is safe for concurrent use.
 type KeyColor struct {
 	// hue is the hue of the key color
 	hue float64
 	// requestedChroma is the chroma of the key color
 	requestedChroma float64
-	// chromaCache maps tone to max chroma to avoid duplicated HCT calculation
-	chromaCache map[float64]float64
+	// chromaCache maps tone to max chroma to avoid duplicated HCT calculation.
+	// It is a sync.Map so that KeyColor is safe for concurrent use.
+	chromaCache sync.Map
 	// maxChromaValue is the maximum possible chroma value
 	maxChromaValue float64
 }
@@ -23,7 +26,6 @@ func NewKeyColor(hue, requestedChroma float64) *KeyColor {
 	return &KeyColor{
 		hue:             hue,
 		requestedChroma: requestedChroma,
-		chromaCache:     map[float64]float64{},
 		maxChromaValue:  200.0,
 	}
 }
@@ -31,12 +33,12 @@ func NewKeyColor(hue, requestedChroma float64) *KeyColor {
 // maxChroma calculates the maximum chroma for a given tone
 // This is a placeholder for the actual implementation
 func (k *KeyColor) maxChroma(tone float64) float64 {
-	if chroma, exists := k.chromaCache[tone]; exists {
-		return chroma
+	if chroma, exists := k.chromaCache.Load(tone); exists {
+		return chroma.(float64)
 	}
 
 	chroma := color.NewHct(k.hue, k.maxChromaValue, tone).Chroma
-	k.chromaCache[tone] = chroma
+	k.chromaCache.Store(tone, chroma)
 	return chroma
 }
 
@@ -1,17 +1,28 @@
 package palettes
 
-import "github.com/Nadim147c/material/v3/color"
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/internal/bin"
+)
 
 // TonalPalette is a convenience type for retrieving colors that are constant in
 // hue and chroma, but vary in tone.
 //
 // Each TonalPalette is initialized with a hue and chroma, and provides a cache
-// for efficient tone retrieval.
+// for efficient tone retrieval. The cache is shared between copies of the
+// palette and is safe for concurrent use. Palettes which are not created by
+// the constructors or decoded from JSON or binary don't cache tones.
 type TonalPalette struct {
-	cache    map[float64]color.ARGB
-	Hue      float64
-	Chroma   float64
-	KeyColor color.Hct
+	cache    *toneCache
+	Hue      float64   `json:"hue"`
+	Chroma   float64   `json:"chroma"`
+	KeyColor color.Hct `json:"key_color"`
 }
 
 // NewFromARGB creates a TonalPalette from an ARGB color.
@@ -28,6 +39,7 @@ func NewFromARGB(c color.ARGB) *TonalPalette {
 // The resulting palette will have the same hue and chroma as the provided HCT.
 func NewFromHct(hct color.Hct) *TonalPalette {
 	return &TonalPalette{
+		cache:    &toneCache{},
 		Hue:      hct.Hue,
 		Chroma:   hct.Chroma,
 		KeyColor: hct,
@@ -43,20 +55,121 @@ func FromHueAndChroma(hue, chroma float64) *TonalPalette {
 	return NewFromHct(keyColor)
 }
 
+// BinarySize is the size of a binary encoded TonalPalette: hue, chroma and the
+// hue, chroma and tone of the key color as float64.
+const BinarySize = 5 * 8
+
+var errTonalPaletteSize = errors.New("invalid tonal palette size")
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (tp *TonalPalette) UnmarshalJSON(data []byte) error {
+	type palette TonalPalette // palette doesn't have the UnmarshalJSON method
+	var v palette
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*tp = TonalPalette(v)
+	tp.cache = &toneCache{}
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (tp TonalPalette) MarshalBinary() ([]byte, error) {
+	return tp.AppendBinary(make([]byte, 0, BinarySize))
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface. The hue,
+// chroma and key color of the palette are appended as big endian float64.
+func (tp TonalPalette) AppendBinary(b []byte) ([]byte, error) {
+	for _, f := range []float64{
+		tp.Hue,
+		tp.Chroma,
+		tp.KeyColor.Hue,
+		tp.KeyColor.Chroma,
+		tp.KeyColor.Tone,
+	} {
+		b = bin.AppendFloat64(b, f)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (tp *TonalPalette) UnmarshalBinary(data []byte) error {
+	if len(data) != BinarySize {
+		return errTonalPaletteSize
+	}
+	var f [5]float64
+	for i := range f {
+		f[i] = bin.Float64(data[i*8:])
+	}
+	*tp = TonalPalette{
+		cache:    &toneCache{},
+		Hue:      f[0],
+		Chroma:   f[1],
+		KeyColor: color.Hct{Hue: f[2], Chroma: f[3], Tone: f[4]},
+	}
+	return nil
+}
+
 // Tone returns the ARGB representation of a color at a given tone (0–100).
 //
 // The tone defines the perceived lightness of the color, where 0 is black and
 // 100 is white. Results are cached for subsequent retrievals.
 func (tp *TonalPalette) Tone(tone float64) color.ARGB {
 	if tp.cache == nil {
-		tp.cache = map[float64]color.ARGB{}
+		return tp.compute(tone)
 	}
+	return tp.cache.load(tone, tp.compute)
+}
 
-	argb, ok := tp.cache[tone]
-	if !ok {
-		argb = color.NewHct(tp.Hue, tp.Chroma, tone).ToARGB()
-		tp.cache[tone] = argb
+// compute returns the ARGB of tone without using the cache
+func (tp *TonalPalette) compute(tone float64) color.ARGB {
+	return color.NewHct(tp.Hue, tp.Chroma, tone).ToARGB()
+}
+
+// toneCache caches the ARGB of tones. Integer tones from 0 to 100, which are
+// the tones of the standard tone ramp, are stored in atomic slots. Other tones
+// are stored in a sync.Map.
+//
+// The tone ramp is filled lazily rather than pre-computed by the constructors.
+// Solving all 101 tones costs about a millisecond per palette, while a scheme
+// only reads a few tones of each of its palettes.
+type toneCache struct {
+	// ramp stores the ARGB of integer tones with rampSet bit, or 0 if the tone
+	// isn't computed yet.
+	ramp  [101]atomic.Uint64
+	other sync.Map
+}
+
+// rampSet marks a computed slot of the tone ramp, since an empty slot is 0. The
+// mark doesn't depend on the ARGB value, e.g. the alpha of the color.
+const rampSet = 1 << 32
+
+// load returns the cached ARGB of tone. The ARGB is computed with compute and
+// cached if it isn't cached yet. Concurrent calls may compute the same tone
+// more than once, but always store the same value.
+func (c *toneCache) load(
+	tone float64,
+	compute func(float64) color.ARGB,
+) color.ARGB {
+	if tone >= 0 && tone <= 100 && tone == math.Trunc(tone) {
+		slot := &c.ramp[int(tone)]
+		if v := slot.Load(); v != 0 {
+			return color.ARGB(v)
+		}
+		argb := compute(tone)
+		slot.Store(uint64(argb) | rampSet)
+		return argb
+	}
+
+	if math.IsNaN(tone) {
+		return compute(tone)
+	}
+	if argb, ok := c.other.Load(tone); ok {
+		return argb.(color.ARGB)
 	}
+	argb := compute(tone)
+	c.other.Store(tone, argb)
 	return argb
 }
 
@@ -0,0 +1,120 @@
+package palettes
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+// tones is integer tones of the tone ramp and fractional tones
+var tones = []float64{0, 4, 10, 17.5, 40, 50, 87.25, 90, 98, 99, 100}
+
+func TestTonalPaletteTone(t *testing.T) {
+	tp := NewFromARGB(0xFF0044FF)
+
+	for _, tone := range tones {
+		want := color.NewHct(tp.Hue, tp.Chroma, tone).ToARGB()
+		for range 2 {
+			if got := tp.Tone(tone); got != want {
+				t.Errorf("Tone(%v) = %s, want %s", tone, got, want)
+			}
+		}
+	}
+
+	var zero TonalPalette
+	zero.Hue, zero.Chroma = tp.Hue, tp.Chroma
+	if got, want := zero.Tone(40), tp.Tone(40); got != want {
+		t.Errorf("Tone(40) of uncached palette = %s, want %s", got, want)
+	}
+}
+
+func TestTonalPaletteConcurrent(t *testing.T) {
+	tp := NewFromARGB(0xFF0044FF)
+	copied := *tp
+
+	want := make(map[float64]color.ARGB, len(tones))
+	for _, tone := range tones {
+		want[tone] = color.NewHct(tp.Hue, tp.Chroma, tone).ToARGB()
+	}
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		p := tp
+		if i%2 == 0 {
+			p = &copied
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, tone := range tones {
+				if got := p.Tone(tone); got != want[tone] {
+					t.Errorf("Tone(%v) = %s, want %s", tone, got, want[tone])
+				}
+				p.GetHct(tone)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestKeyColorConcurrent(t *testing.T) {
+	want := NewKeyColor(270, 36).Create()
+
+	k := NewKeyColor(270, 36)
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := k.Create(); got != want {
+				t.Errorf("Create() = %v, want %v", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTonalPaletteEncoding(t *testing.T) {
+	want := FromHueAndChroma(270, 36)
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("failed to marshal palette: %v", err)
+	}
+	var fromJSON TonalPalette
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("failed to unmarshal palette: %v", err)
+	}
+
+	data, err = want.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal palette: %v", err)
+	}
+	var fromBinary TonalPalette
+	if err := fromBinary.UnmarshalBinary(data); err != nil {
+		t.Fatalf("failed to unmarshal palette: %v", err)
+	}
+
+	for name, got := range map[string]TonalPalette{
+		"json":   fromJSON,
+		"binary": fromBinary,
+	} {
+		if got.Hue != want.Hue || got.Chroma != want.Chroma ||
+			got.KeyColor != want.KeyColor {
+			t.Errorf("%s: palette = %+v, want %+v", name, got, *want)
+		}
+		if got.cache == nil {
+			t.Errorf("%s: decoded palette has no cache", name)
+		}
+		if got.Tone(40) != want.Tone(40) {
+			t.Errorf("%s: Tone(40) = %s, want %s",
+				name, got.Tone(40), want.Tone(40))
+		}
+	}
+
+	if err := fromBinary.UnmarshalBinary(data[1:]); err == nil {
+		t.Errorf("UnmarshalBinary() of truncated data succeeded")
+	}
+}
@@ -15,8 +15,17 @@ import (
 // This algorithm was designed by M. Emre Celebi, and was found in their 2011
 // paper, Improving the Performance of K-Means for Color Quantization.
 // https://arxiv.org/abs/1101.0395
-func QuantizeCelebi(input []color.ARGB, maxColor int) QuantizedMap {
-	qm, _ := QuantizeCelebiContext(context.Background(), input, maxColor)
+func QuantizeCelebi(
+	input []color.ARGB,
+	maxColor int,
+	opts ...Option,
+) QuantizedMap {
+	qm, _ := QuantizeCelebiContext(
+		context.Background(),
+		input,
+		maxColor,
+		opts...,
+	)
 	return qm
 }
 
@@ -26,12 +35,13 @@ func QuantizeCelebiContext(
 	ctx context.Context,
 	input []color.ARGB,
 	maxColor int,
+	opts ...Option,
 ) (QuantizedMap, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
-	wu, err := QuantizeWuContext(ctx, input, maxColor*5)
+	wu, err := QuantizeWuContext(ctx, input, maxColor*5, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +56,7 @@ func QuantizeCelebiContext(
 		colors[i] = c.ToLab()
 	}
 
-	qm, err := QuantizeWsMeansContext(ctx, input, colors, maxColor)
+	qm, err := QuantizeWsMeansContext(ctx, input, colors, maxColor, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,66 @@
+package quantizer
+
+import (
+	"context"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+// Histogram is the color histogram used by the Wu quantizer. Pixels can be
+// added incrementally, so large pixel streams can be quantized without keeping
+// the pixels in memory. The histogram has 32 levels per channel and uses a
+// constant amount of memory.
+//
+// Histogram is not safe for concurrent use.
+type Histogram struct {
+	wu quantizerWu
+}
+
+// NewHistogram returns an empty histogram. WithAlphaWeighting is supported,
+// other options are ignored.
+func NewHistogram(opts ...Option) *Histogram {
+	o := newOptions(opts)
+	return &Histogram{
+		wu: quantizerWu{opts: options{alphaWeighting: o.alphaWeighting}},
+	}
+}
+
+// Add adds pixels to the histogram.
+func (h *Histogram) Add(pixels []color.ARGB) {
+	h.wu.BuildHistogram(context.Background(), pixels)
+}
+
+// Colors returns the average color of each non-empty level of the histogram
+// with the number of pixels it represents.
+func (h *Histogram) Colors() QuantizedMap {
+	qm := QuantizedMap{}
+	for i, weight := range &h.wu.weights {
+		if weight <= 0 {
+			continue
+		}
+		r := uint8(h.wu.momentsR[i] / weight)
+		g := uint8(h.wu.momentsG[i] / weight)
+		b := uint8(h.wu.momentsB[i] / weight)
+		qm[color.ARGBFromRGB(r, g, b)] += h.wu.population(weight)
+	}
+	return qm
+}
+
+// Quantize quantizes the pixels added so far with the Wu algorithm. The
+// result is same as quantizing all the added pixels with Wu. More pixels can
+// be added after quantizing.
+func (h *Histogram) Quantize(
+	ctx context.Context,
+	maxColors int,
+) (QuantizedMap, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	wu := h.wu
+	r, err := wu.boxes(ctx, maxColors)
+	if err != nil {
+		return nil, err
+	}
+	return wu.CreateResultMap(r), nil
+}
@@ -0,0 +1,46 @@
+package quantizer
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	pixels := gopharPixels(t)
+
+	h := NewHistogram()
+	for chunk := range slices.Chunk(pixels, 4096) {
+		h.Add(chunk)
+	}
+
+	got, err := h.Quantize(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("Quantize() failed: %v", err)
+	}
+	want, _ := Wu{}.Quantize(context.Background(), pixels, 5)
+	if !maps.Equal(got, want) {
+		t.Errorf("Histogram.Quantize() = %v, want %v", got, want)
+	}
+
+	again, _ := h.Quantize(context.Background(), 5)
+	if !maps.Equal(again, want) {
+		t.Errorf("second Histogram.Quantize() = %v, want %v", again, want)
+	}
+
+	population := 0
+	for c := range maps.Values(h.Colors()) {
+		population += c
+	}
+	if population != len(pixels) {
+		t.Errorf("population of Colors() = %d, want %d",
+			population, len(pixels))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := h.Quantize(ctx, 5); err != context.Canceled {
+		t.Errorf("Quantize() error = %v, want %v", err, context.Canceled)
+	}
+}
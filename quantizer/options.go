@@ -0,0 +1,111 @@
+package quantizer
+
+import (
+	"math/rand"
+	"slices"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+// options configures the quantizers
+type options struct {
+	seed           int64
+	seeded         bool
+	source         rand.Source
+	alphaWeighting bool
+	weights        []float64
+}
+
+// Option is a function that modifies the underlying options
+type Option func(*options)
+
+// WithSeed makes the quantization reproducible by using a random source seeded
+// with seed.
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.seed = seed
+		o.seeded = true
+		o.source = nil
+	}
+}
+
+// WithRandSource sets the random source used for the initial clusters. The
+// source is not safe for concurrent use and shouldn't be shared between
+// concurrent quantizations.
+func WithRandSource(src rand.Source) Option {
+	return func(o *options) {
+		o.source = src
+		o.seeded = false
+	}
+}
+
+// WithAlphaWeighting weights every pixel by its alpha instead of counting it as
+// a whole pixel. Colors are un-premultiplied before quantization and fully
+// transparent pixels are ignored. By default Wu ignores every pixel which is
+// not fully opaque.
+func WithAlphaWeighting() Option {
+	return func(o *options) { o.alphaWeighting = true }
+}
+
+// WithWeights weights the i-th pixel by weights[i] instead of counting it as a
+// whole pixel. Pixels without a weight have weight 1 and pixels with weight of
+// 0 or less are ignored. Populations are the weighted sum of the pixels rounded
+// to the nearest integer, but never less than 1.
+func WithWeights(weights []float64) Option {
+	return func(o *options) { o.weights = weights }
+}
+
+// newOptions returns options modified by opts
+func newOptions(opts []Option) options {
+	var o options
+	for opt := range slices.Values(opts) {
+		opt(&o)
+	}
+	return o
+}
+
+// rand returns the random functions for int in [0, n) and float in [0, 1). Uses
+// the global random source if neither seed nor source is set.
+func (o options) rand() (intn func(int) int, float func() float64) {
+	var r *rand.Rand
+	switch {
+	case o.source != nil:
+		r = rand.New(o.source)
+	case o.seeded:
+		r = rand.New(rand.NewSource(o.seed))
+	default:
+		return rand.Intn, rand.Float64
+	}
+	return r.Intn, r.Float64
+}
+
+// weight returns the weight of the i-th pixel
+func (o options) weight(i int) float64 {
+	if i < len(o.weights) {
+		return max(o.weights[i], 0)
+	}
+	return 1
+}
+
+// weighted reports whether the pixels aren't counted as whole pixels
+func (o options) weighted() bool {
+	return o.alphaWeighting || o.weights != nil
+}
+
+// unpremultiply returns the opaque color of the alpha premultiplied color c
+// and the alpha of c as weight.
+func unpremultiply(c color.ARGB) (color.ARGB, int64) {
+	a := uint32(c.Alpha())
+	switch a {
+	case 0:
+		return 0, 0
+	case 0xFF:
+		return c, 0xFF
+	}
+
+	channel := func(v uint8) uint8 {
+		return uint8(min(0xFF, (uint32(v)*0xFF+a/2)/a))
+	}
+	return color.NewARGB(0xFF, channel(c.Red()), channel(c.Green()),
+		channel(c.Blue())), int64(a)
+}
@@ -0,0 +1,119 @@
+package quantizer
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+func TestUnpremultiply(t *testing.T) {
+	tests := []struct {
+		in     color.ARGB
+		want   color.ARGB
+		weight int64
+	}{
+		{0x00000000, 0, 0},
+		{0x00FFFFFF, 0, 0},
+		{0xFF123456, 0xFF123456, 0xFF},
+		{0x80800000, 0xFFFF0000, 0x80},
+		{0x40102030, 0xFF4080BF, 0x40},
+	}
+
+	for _, tc := range tests {
+		got, weight := unpremultiply(tc.in)
+		if got != tc.want || weight != tc.weight {
+			t.Errorf("unpremultiply(%a) = %a, %d, want %a, %d",
+				tc.in, got, weight, tc.want, tc.weight)
+		}
+	}
+}
+
+func TestAlphaWeighting(t *testing.T) {
+	red := color.ARGB(0xFFFF0000)
+	blue := color.ARGB(0xFF0000FF)
+
+	var pixels []color.ARGB
+	for range 100 {
+		pixels = append(pixels, 0x00000000)
+	}
+	for range 10 {
+		pixels = append(pixels, red, 0x80000080)
+	}
+
+	opts := []Option{WithAlphaWeighting(), WithSeed(1)}
+	quantizers := map[string]Quantizer{
+		"celebi": Celebi{Options: opts},
+		"wu":     Wu{Options: opts},
+		"wsmeans": WsMeans{
+			StartingClusters: []color.Lab{red.ToLab(), blue.ToLab()},
+			Options:          opts,
+		},
+	}
+
+	for name, q := range quantizers {
+		t.Run(name, func(t *testing.T) {
+			result, err := q.Quantize(context.Background(), pixels, 4)
+			if err != nil {
+				t.Fatalf("Quantize() failed: %v", err)
+			}
+
+			if len(result) != 2 {
+				t.Fatalf("Quantize() = %v, want red and blue", result)
+			}
+			if result[red] != 10 {
+				t.Errorf("population of red = %d, want 10", result[red])
+			}
+			if result[blue] != 5 {
+				t.Errorf("population of blue = %d, want 5", result[blue])
+			}
+		})
+	}
+
+	result := QuantizeWu(pixels, 4)
+	if len(result) != 1 || result[0] != red {
+		t.Errorf("QuantizeWu() without alpha weighting = %v, want [%s]",
+			result, red)
+	}
+}
+
+func TestWithWeights(t *testing.T) {
+	red := color.ARGB(0xFFFF0000)
+	blue := color.ARGB(0xFF0000FF)
+	green := color.ARGB(0xFF00FF00)
+
+	var pixels []color.ARGB
+	var weights []float64
+	for range 10 {
+		pixels = append(pixels, red, blue, green)
+		weights = append(weights, 1, 0.2, 0)
+	}
+
+	quantizers := map[string]WeightedQuantizer{
+		"celebi": Celebi{Options: []Option{WithSeed(1)}},
+		"wu":     Wu{},
+		"wsmeans": WsMeans{
+			StartingClusters: []color.Lab{red.ToLab(), blue.ToLab()},
+		},
+	}
+
+	for name, q := range quantizers {
+		t.Run(name, func(t *testing.T) {
+			result, err := q.QuantizeWeighted(
+				context.Background(),
+				pixels,
+				weights,
+				4,
+			)
+			if err != nil {
+				t.Fatalf("QuantizeWeighted() failed: %v", err)
+			}
+
+			want := QuantizedMap{red: 10, blue: 2}
+			if !maps.Equal(result, want) {
+				t.Errorf("QuantizeWeighted() = %v, want %v", result, want)
+			}
+		})
+	}
+}
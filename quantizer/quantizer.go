@@ -0,0 +1,152 @@
+package quantizer
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+// Quantizer reduces pixels into at most maxColors colors with their
+// population.
+type Quantizer interface {
+	// Quantize returns at most maxColors colors of pixels with the number of
+	// pixels each color represents. Returns ctx.Err() if context is Done.
+	Quantize(
+		ctx context.Context,
+		pixels []color.ARGB,
+		maxColors int,
+	) (QuantizedMap, error)
+}
+
+// WeightedQuantizer is a Quantizer which can weight each pixel instead of
+// counting it as a whole pixel.
+type WeightedQuantizer interface {
+	Quantizer
+	// QuantizeWeighted is Quantize where weights[i] is the weight of
+	// pixels[i]. Populations are the rounded weighted sums of the pixels, see
+	// WithWeights.
+	QuantizeWeighted(
+		ctx context.Context,
+		pixels []color.ARGB,
+		weights []float64,
+		maxColors int,
+	) (QuantizedMap, error)
+}
+
+// Func is an adapter to use ordinary functions as Quantizer.
+type Func func(
+	ctx context.Context,
+	pixels []color.ARGB,
+	maxColors int,
+) (QuantizedMap, error)
+
+var (
+	_ Quantizer = Func(nil)
+	_ Quantizer = Celebi{}
+	_ Quantizer = Wu{}
+	_ Quantizer = WsMeans{}
+
+	_ WeightedQuantizer = Celebi{}
+	_ WeightedQuantizer = Wu{}
+	_ WeightedQuantizer = WsMeans{}
+)
+
+// Quantize calls f(ctx, pixels, maxColors).
+func (f Func) Quantize(
+	ctx context.Context,
+	pixels []color.ARGB,
+	maxColors int,
+) (QuantizedMap, error) {
+	return f(ctx, pixels, maxColors)
+}
+
+// Celebi is a Quantizer using QuantizeCelebiContext.
+type Celebi struct {
+	Options []Option
+}
+
+// Quantize implements Quantizer.
+func (c Celebi) Quantize(
+	ctx context.Context,
+	pixels []color.ARGB,
+	maxColors int,
+) (QuantizedMap, error) {
+	return QuantizeCelebiContext(ctx, pixels, maxColors, c.Options...)
+}
+
+// QuantizeWeighted implements WeightedQuantizer.
+func (c Celebi) QuantizeWeighted(
+	ctx context.Context,
+	pixels []color.ARGB,
+	weights []float64,
+	maxColors int,
+) (QuantizedMap, error) {
+	c.Options = append(slices.Clip(c.Options), WithWeights(weights))
+	return c.Quantize(ctx, pixels, maxColors)
+}
+
+// Wu is a Quantizer using the Wu algorithm. The population of each color is the
+// number of opaque pixels in its box, or the alpha weighted number of pixels
+// with WithAlphaWeighting. It is deterministic and faster than Celebi.
+type Wu struct {
+	Options []Option
+}
+
+// Quantize implements Quantizer.
+func (w Wu) Quantize(
+	ctx context.Context,
+	pixels []color.ARGB,
+	maxColors int,
+) (QuantizedMap, error) {
+	q := newQuantizerWu(newOptions(w.Options))
+	r, err := q.Boxes(ctx, pixels, maxColors)
+	if err != nil {
+		return nil, err
+	}
+	return q.CreateResultMap(r), nil
+}
+
+// QuantizeWeighted implements WeightedQuantizer.
+func (w Wu) QuantizeWeighted(
+	ctx context.Context,
+	pixels []color.ARGB,
+	weights []float64,
+	maxColors int,
+) (QuantizedMap, error) {
+	w.Options = append(slices.Clip(w.Options), WithWeights(weights))
+	return w.Quantize(ctx, pixels, maxColors)
+}
+
+// WsMeans is a Quantizer using QuantizeWsMeansContext. Random clusters are used
+// when StartingClusters is empty.
+type WsMeans struct {
+	StartingClusters []color.Lab
+	Options          []Option
+}
+
+// Quantize implements Quantizer.
+func (w WsMeans) Quantize(
+	ctx context.Context,
+	pixels []color.ARGB,
+	maxColors int,
+) (QuantizedMap, error) {
+	return QuantizeWsMeansContext(
+		ctx,
+		pixels,
+		w.StartingClusters,
+		maxColors,
+		w.Options...,
+	)
+}
+
+// QuantizeWeighted implements WeightedQuantizer.
+func (w WsMeans) QuantizeWeighted(
+	ctx context.Context,
+	pixels []color.ARGB,
+	weights []float64,
+	maxColors int,
+) (QuantizedMap, error) {
+	w.Options = append(slices.Clip(w.Options), WithWeights(weights))
+	return w.Quantize(ctx, pixels, maxColors)
+}
@@ -0,0 +1,95 @@
+package quantizer
+
+import (
+	"context"
+	"errors"
+	"image/jpeg"
+	"maps"
+	"testing"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+func gopharPixels(t *testing.T) []color.ARGB {
+	t.Helper()
+
+	file, err := gophar.Open("gophar.jpg")
+	if err != nil {
+		t.Fatalf("failed to open image: %v", err)
+	}
+	defer file.Close()
+
+	img, err := jpeg.Decode(file)
+	if err != nil {
+		t.Fatalf("failed to decode image: %v", err)
+	}
+
+	var pixels []color.ARGB
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pixels = append(pixels, color.ARGBFromInterface(img.At(x, y)))
+		}
+	}
+	return pixels
+}
+
+func TestQuantizers(t *testing.T) {
+	pixels := gopharPixels(t)
+
+	quantizers := map[string]Quantizer{
+		"celebi":  Celebi{},
+		"wu":      Wu{},
+		"wsmeans": WsMeans{},
+		"func": Func(func(
+			ctx context.Context,
+			pixels []color.ARGB,
+			maxColors int,
+		) (QuantizedMap, error) {
+			return QuantizeCelebiContext(ctx, pixels, maxColors)
+		}),
+	}
+
+	for name, q := range quantizers {
+		t.Run(name, func(t *testing.T) {
+			result, err := q.Quantize(context.Background(), pixels, 5)
+			if err != nil {
+				t.Fatalf("Quantize() failed: %v", err)
+			}
+			if len(result) == 0 || len(result) > 5 {
+				t.Fatalf("Quantize() returned %d colors", len(result))
+			}
+
+			population := 0
+			for _, count := range result {
+				population += count
+			}
+			if population <= 0 || population > len(pixels) {
+				t.Errorf("total population = %d, want in (0, %d]",
+					population, len(pixels))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err = q.Quantize(ctx, pixels, 5)
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Quantize() with canceled context returned %v", err)
+			}
+		})
+	}
+}
+
+func TestWuQuantizer(t *testing.T) {
+	result, err := Wu{}.Quantize(context.Background(), gopharPixels(t), 2)
+	if err != nil {
+		t.Fatalf("Quantize() failed: %v", err)
+	}
+
+	want := QuantizedMap{
+		color.ARGBFromHexMust("#0E1213"): 83084,
+		color.ARGBFromHexMust("#C2E9ED"): 965492,
+	}
+	if !maps.Equal(result, want) {
+		t.Errorf("Quantize() = %v, want %v", result, want)
+	}
+}
@@ -2,8 +2,8 @@ package quantizer
 
 import (
 	"context"
+	"maps"
 	"math"
-	"math/rand"
 	"slices"
 
 	"github.com/Nadim147c/material/v3/color"
@@ -22,7 +22,8 @@ type distanceIndex struct {
 
 type pixelsLab = []color.Lab
 
-// QuantizedMap is a map where ARGB is key and their frequencies as int
+// QuantizedMap is a map where ARGB is key and their frequencies as int. With
+// weighted pixels, frequencies are the rounded sums of the weights.
 type QuantizedMap = map[color.ARGB]int
 
 // QuantizeWsMeans is an image quantizer that improves on the speed of a
@@ -40,6 +41,7 @@ func QuantizeWsMeans(
 	input []color.ARGB,
 	startingClusters []color.Lab,
 	maxColors int,
+	opts ...Option,
 ) QuantizedMap {
 	// ignore error because background context won't return any error
 	qm, _ := QuantizeWsMeansContext(
@@ -47,6 +49,7 @@ func QuantizeWsMeans(
 		input,
 		startingClusters,
 		maxColors,
+		opts...,
 	)
 	return qm
 }
@@ -57,37 +60,48 @@ func QuantizeWsMeansContext(
 	input []color.ARGB,
 	startingClusters []color.Lab,
 	maxColors int,
+	opts ...Option,
 ) (QuantizedMap, error) {
 	// Check context at the start
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
+	o := newOptions(opts)
+	intn, float := o.rand()
+
 	// Get color frequencies
-	freq := QuantizedMap{}
-	for c := range slices.Values(input) {
+	freq := map[color.ARGB]float64{}
+	for i, c := range input {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		default:
 		}
-		freq[c]++
+		weight := o.weight(i)
+		if o.alphaWeighting {
+			var alpha int64
+			c, alpha = unpremultiply(c)
+			weight *= float64(alpha) / 0xFF
+		}
+		if weight > 0 {
+			freq[c] += weight
+		}
 	}
 
-	// Number of unique color in the image/pixels array
+	// Number of unique color in the image/pixels array. Points are sorted so
+	// that the result only depends on the random source.
 	pointCount := len(freq)
 	points := make(pixelsLab, pointCount)
-	counts := make([]int, pointCount)
-	i := 0
-	for k, v := range freq {
+	counts := make([]float64, pointCount)
+	for i, k := range slices.Sorted(maps.Keys(freq)) {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		default:
 		}
 		points[i] = k.ToLab()
-		counts[i] = v
-		i++
+		counts[i] = freq[k]
 	}
 
 	clusterCount := min(maxColors, pointCount)
@@ -103,12 +117,12 @@ func QuantizeWsMeansContext(
 
 	clustersNeeded := clusterCount - len(clusters)
 	if len(startingClusters) == 0 && clustersNeeded > 0 {
-		clusters = append(clusters, randomLabClusters(clustersNeeded)...)
+		clusters = append(clusters, randomLabClusters(clustersNeeded, float)...)
 	}
 
 	clusterIndices := make([]int, pointCount)
 	for i := range clusterIndices {
-		clusterIndices[i] = rand.Intn(clusterCount)
+		clusterIndices[i] = intn(clusterCount)
 	}
 
 	indexMatrix := make([][]int, clusterCount)
@@ -219,7 +233,7 @@ func QuantizeWsMeansContext(
 
 			clusterIndex := clusterIndices[i]
 			point := points[i]
-			count := float64(counts[i])
+			count := counts[i]
 
 			pixelCountSums[clusterIndex] += count
 			component0Sums[clusterIndex] += point.L * count
@@ -245,10 +259,10 @@ func QuantizeWsMeansContext(
 		argbToPopulation := QuantizedMap{}
 
 		for i := range clusterCount {
-			count := int(pixelCountSums[i])
-			if count == 0 {
+			if pixelCountSums[i] == 0 {
 				continue
 			}
+			count := max(1, int(math.Round(pixelCountSums[i])))
 
 			colorInt := clusters[i].ToARGB()
 			if _, exists := argbToPopulation[colorInt]; exists {
@@ -269,12 +283,12 @@ func QuantizeWsMeansContext(
 	return result, nil
 }
 
-func randomLabClusters(n int) []color.Lab {
+func randomLabClusters(n int, float func() float64) []color.Lab {
 	clusters := make([]color.Lab, n)
 	for i := range n {
-		l := rand.Float64() * 100.0
-		a := rand.Float64()*200.0 - 100.0
-		b := rand.Float64()*200.0 - 100.0
+		l := float() * 100.0
+		a := float()*200.0 - 100.0
+		b := float()*200.0 - 100.0
 		clusters[i] = color.NewLab(l, a, b)
 	}
 	return clusters
@@ -2,6 +2,8 @@ package quantizer
 
 import (
 	"image/jpeg"
+	"maps"
+	"math/rand"
 	"testing"
 
 	"github.com/Nadim147c/material/v3/color"
@@ -34,3 +36,41 @@ func TestQuantizeWsMeans(t *testing.T) {
 		t.Logf("Cluster %s %s: %d", c.HexRGB(), c.AnsiBg("  "), count)
 	}
 }
+
+func TestQuantizeWsMeansSeed(t *testing.T) {
+	pixels := gopharPixels(t)
+
+	tests := []struct {
+		name     string
+		quantize func() QuantizedMap
+	}{
+		{"wsmeans seed", func() QuantizedMap {
+			return QuantizeWsMeans(pixels, nil, 5, WithSeed(42))
+		}},
+		{"wsmeans source", func() QuantizedMap {
+			return QuantizeWsMeans(pixels, nil, 5,
+				WithRandSource(rand.NewSource(42)))
+		}},
+		{"celebi seed", func() QuantizedMap {
+			return QuantizeCelebi(pixels, 5, WithSeed(42))
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			want := tc.quantize()
+			for range 5 {
+				if got := tc.quantize(); !maps.Equal(got, want) {
+					t.Fatalf("seeded quantization differs: %v != %v", got, want)
+				}
+			}
+		})
+	}
+
+	seeded := QuantizeWsMeans(pixels, nil, 5, WithSeed(42))
+	sourced := QuantizeWsMeans(pixels, nil, 5,
+		WithRandSource(rand.NewSource(42)))
+	if !maps.Equal(seeded, sourced) {
+		t.Errorf("WithSeed(42) and WithRandSource(NewSource(42)) differ")
+	}
+}
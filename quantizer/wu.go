@@ -2,7 +2,7 @@ package quantizer
 
 import (
 	"context"
-	"slices"
+	"math"
 
 	"github.com/Nadim147c/material/v3/color"
 )
@@ -13,6 +13,10 @@ const (
 	histSize     int64 = 32 // 32 bins
 	cubeSize     int64 = 33 // 32 bins + 1 for cumulative indexing
 	totalSize    int64 = 35937
+
+	// wuUnit is the histogram weight of a whole opaque pixel when pixels are
+	// weighted. Pixel weights are multiplied by the alpha of the pixel.
+	wuUnit int64 = 0xFF
 )
 
 func index(r, g, b int64) int64 {
@@ -40,6 +44,9 @@ type maximizeResult struct {
 }
 
 type quantizerWu struct {
+	// opts configures how pixels are weighted
+	opts options
+
 	weights  [totalSize]int64
 	momentsR [totalSize]int64
 	momentsG [totalSize]int64
@@ -54,9 +61,13 @@ type quantizerWu struct {
 //
 // The algorithm was described by Xiaolin Wu in Graphic Gems II, published in
 // 1991.
-func QuantizeWu(input []color.ARGB, maxColor int) []color.ARGB {
+func QuantizeWu(
+	input []color.ARGB,
+	maxColor int,
+	opts ...Option,
+) []color.ARGB {
 	// ignore error because background context won't return any error
-	qw, _ := QuantizeWuContext(context.Background(), input, maxColor)
+	qw, _ := QuantizeWuContext(context.Background(), input, maxColor, opts...)
 	return qw
 }
 
@@ -65,9 +76,14 @@ func QuantizeWuContext(
 	ctx context.Context,
 	input []color.ARGB,
 	maxColor int,
+	opts ...Option,
 ) ([]color.ARGB, error) {
-	q := &quantizerWu{}
-	return q.Quantize(ctx, input, maxColor)
+	return newQuantizerWu(newOptions(opts)).Quantize(ctx, input, maxColor)
+}
+
+// newQuantizerWu returns a Wu quantizer configured by o
+func newQuantizerWu(o options) *quantizerWu {
+	return &quantizerWu{opts: o}
 }
 
 func (q *quantizerWu) Quantize(
@@ -75,33 +91,66 @@ func (q *quantizerWu) Quantize(
 	input []color.ARGB,
 	maxColor int,
 ) ([]color.ARGB, error) {
+	r, err := q.Boxes(ctx, input, maxColor)
+	if err != nil {
+		return nil, err
+	}
+	return q.CreateResult(r), nil
+}
+
+// Boxes cuts the RGB cube of input into at most maxColor boxes and returns the
+// number of boxes.
+func (q *quantizerWu) Boxes(
+	ctx context.Context,
+	input []color.ARGB,
+	maxColor int,
+) (int, error) {
 	q.BuildHistogram(ctx, input)
 	if ctx.Err() != nil {
-		return nil, ctx.Err()
+		return 0, ctx.Err()
 	}
+	return q.boxes(ctx, maxColor)
+}
+
+// boxes cuts the RGB cube of the histogram into at most maxColor boxes and
+// returns the number of boxes. The histogram is replaced by its moments.
+func (q *quantizerWu) boxes(ctx context.Context, maxColor int) (int, error) {
 	q.ComputeMoments(ctx)
 	if ctx.Err() != nil {
-		return nil, ctx.Err()
+		return 0, ctx.Err()
 	}
 	r := q.CreateBoxes(ctx, maxColor)
 	if ctx.Err() != nil {
-		return nil, ctx.Err()
+		return 0, ctx.Err()
 	}
-	return q.CreateResult(r), nil
+	return r, nil
 }
 
 func (q *quantizerWu) BuildHistogram(ctx context.Context, pixels []color.ARGB) {
-	for chunk := range slices.Chunk(pixels, 100) {
+	weighted := q.opts.weighted()
+	for start := 0; start < len(pixels); start += 100 {
 		select {
 		case <-ctx.Done():
 			return
 		default:
 		}
 
-		for pixel := range slices.Values(chunk) {
-			if pixel.Alpha() != 0xFF {
+		for i := start; i < min(start+100, len(pixels)); i++ {
+			pixel := pixels[i]
+			weight := int64(1)
+			alpha := int64(0xFF)
+			if q.opts.alphaWeighting {
+				pixel, alpha = unpremultiply(pixel)
+			} else if pixel.Alpha() != 0xFF {
 				continue
 			}
+			if weighted {
+				weight = int64(math.Round(q.opts.weight(i) * float64(alpha)))
+			}
+			if weight == 0 {
+				continue
+			}
+
 			red := int64(pixel.Red())
 			green := int64(pixel.Green())
 			blue := int64(pixel.Blue())
@@ -112,11 +161,11 @@ func (q *quantizerWu) BuildHistogram(ctx context.Context, pixels []color.ARGB) {
 
 			i := index(ri, gi, bi)
 
-			q.weights[i]++
-			q.momentsR[i] += red
-			q.momentsG[i] += green
-			q.momentsB[i] += blue
-			q.moments[i] += (red*red + green*green + blue*blue)
+			q.weights[i] += weight
+			q.momentsR[i] += red * weight
+			q.momentsG[i] += green * weight
+			q.momentsB[i] += blue * weight
+			q.moments[i] += (red*red + green*green + blue*blue) * weight
 		}
 	}
 }
@@ -137,6 +186,32 @@ func (q *quantizerWu) CreateResult(maxColor int) []color.ARGB {
 	return colors
 }
 
+// CreateResultMap returns the average color of each box with the number of
+// pixels in the box. With alpha weighting or pixel weights, the population is
+// the rounded sum of the weights of the pixels.
+func (q *quantizerWu) CreateResultMap(maxColor int) QuantizedMap {
+	qm := QuantizedMap{}
+	for i := range maxColor {
+		cube := q.cubes[i]
+		weight := q.Volume(&cube, &q.weights)
+		if weight > 0 {
+			r := uint8(q.Volume(&cube, &q.momentsR) / weight)
+			g := uint8(q.Volume(&cube, &q.momentsG) / weight)
+			b := uint8(q.Volume(&cube, &q.momentsB) / weight)
+			qm[color.ARGBFromRGB(r, g, b)] += q.population(weight)
+		}
+	}
+	return qm
+}
+
+// population returns the number of pixels of the histogram weight
+func (q *quantizerWu) population(weight int64) int {
+	if q.opts.weighted() {
+		return int(max(1, (weight+wuUnit/2)/wuUnit))
+	}
+	return int(weight)
+}
+
 func (q *quantizerWu) CreateBoxes(ctx context.Context, maxColors int) int {
 	q.cubes = make([]box, maxColors)
 	volumeVariance := make([]int64, maxColors)
@@ -185,8 +260,6 @@ func (q *quantizerWu) CreateBoxes(ctx context.Context, maxColors int) int {
 }
 
 func (q *quantizerWu) ComputeMoments(ctx context.Context) {
-	var area, areaR, areaG, areaB, area2 [cubeSize]int64
-
 	for r := int64(1); r < cubeSize; r++ {
 		select {
 		case <-ctx.Done():
@@ -194,6 +267,9 @@ func (q *quantizerWu) ComputeMoments(ctx context.Context) {
 		default:
 		}
 
+		// Areas are the cumulative sums of the current r plane only.
+		var area, areaR, areaG, areaB, area2 [cubeSize]int64
+
 		for g := int64(1); g < cubeSize; g++ {
 			var line, line2, lineR, lineG, lineB int64
 			for b := int64(1); b < cubeSize; b++ {
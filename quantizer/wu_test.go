@@ -39,8 +39,8 @@ func TestQuantizeWu(t *testing.T) {
 		t.Fatal("QuantizeWu() returned no colors")
 	}
 
-	c1 := color.ARGBFromHexMust("#0A0D0E")
-	c2 := color.ARGBFromHexMust("#79D0DB")
+	c1 := color.ARGBFromHexMust("#0E1213")
+	c2 := color.ARGBFromHexMust("#C2E9ED")
 
 	if len(result) != 2 {
 		t.Fatalf("Result: %v has unexpected number of color", result)
@@ -1,6 +1,7 @@
 package score
 
 import (
+	"maps"
 	"math"
 	"slices"
 	"sort"
@@ -56,6 +57,7 @@ func WithFilter() Option {
 
 // scoredColor holds a color and its calculated score
 type scoredColor struct {
+	argb  color.ARGB
 	hct   color.Hct
 	score float64
 }
@@ -90,13 +92,16 @@ func Score(
 
 	// Get the HCT color for each Argb value, while finding the per hue count
 	// and total count.
-	colorsHct := []color.Hct{}
+	colorsHct := []scoredColor{}
 	huePopulation := make([]int, 360)
 	populationSum := 0
 
-	for argb, population := range colorsToPopulation {
+	// Iterate in sorted order so that colors with equal scores are ranked
+	// deterministically.
+	for _, argb := range slices.Sorted(maps.Keys(colorsToPopulation)) {
+		population := colorsToPopulation[argb]
 		hct := argb.ToHct()
-		colorsHct = append(colorsHct, hct)
+		colorsHct = append(colorsHct, scoredColor{argb: argb, hct: hct})
 		hue := int(math.Floor(hct.Hue))
 		huePopulation[hue] += population
 		populationSum += population
@@ -115,7 +120,8 @@ func Score(
 	// Scores each HCT color based on usage and chroma, while optionally
 	// filtering out values that do not have enough chroma or usage.
 	scoredHct := []scoredColor{}
-	for hct := range slices.Values(colorsHct) {
+	for scored := range slices.Values(colorsHct) {
+		hct := scored.hct
 		hue := num.NormalizeDegreeInt(int(math.Round(hct.Hue)))
 		proportion := hueExcitedProportions[hue]
 
@@ -134,13 +140,13 @@ func Score(
 		}
 
 		chromaScore := (hct.Chroma - targetChroma) * chromaWeight
-		score := proportionScore + chromaScore
+		scored.score = proportionScore + chromaScore
 
-		scoredHct = append(scoredHct, scoredColor{hct: hct, score: score})
+		scoredHct = append(scoredHct, scored)
 	}
 
 	// Sort so that colors with higher scores come first
-	sort.Slice(scoredHct, func(i, j int) bool {
+	sort.SliceStable(scoredHct, func(i, j int) bool {
 		return scoredHct[i].score > scoredHct[j].score
 	})
 
@@ -148,17 +154,17 @@ func Score(
 	// the colors with the largest distribution of hues possible. Starting at
 	// 90 degrees(maximum difference for 4 colors) then decreasing down to a
 	// 15 degree minimum.
-	chosenColors := []color.Hct{}
+	chosenColors := []scoredColor{}
 	for differenceDegrees := float64(90); differenceDegrees >= 15; differenceDegrees-- {
-		chosenColors = []color.Hct{} // Clear the array
+		chosenColors = []scoredColor{} // Clear the array
 
 		for scored := range slices.Values(scoredHct) {
 			duplicateHue := false
 
-			for chosenHct := range slices.Values(chosenColors) {
+			for chosen := range slices.Values(chosenColors) {
 				if num.DifferenceDegrees(
 					scored.hct.Hue,
-					chosenHct.Hue,
+					chosen.hct.Hue,
 				) < differenceDegrees {
 					duplicateHue = true
 					break
@@ -166,7 +172,7 @@ func Score(
 			}
 
 			if !duplicateHue {
-				chosenColors = append(chosenColors, scored.hct)
+				chosenColors = append(chosenColors, scored)
 			}
 
 			if len(chosenColors) >= opts.Limit {
@@ -179,14 +185,15 @@ func Score(
 		}
 	}
 
-	// Convert chosen HCT colors back to ARGB integers
+	// Return the original ARGB of the chosen colors so that they can be used
+	// as keys of colorsToPopulation.
 	colors := []color.ARGB{}
 	if len(chosenColors) == 0 {
 		colors = append(colors, opts.Fallback)
 	}
 
-	for chosenHct := range slices.Values(chosenColors) {
-		colors = append(colors, chosenHct.ToARGB())
+	for chosen := range slices.Values(chosenColors) {
+		colors = append(colors, chosen.argb)
 	}
 
 	return colors
@@ -152,4 +152,24 @@ func TestScoring(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("returns input colors unchanged", func(t *testing.T) {
+		colorsToPopulation := map[color.ARGB]int{
+			color.ARGBFromHexMust("#0B48CF"): 3,
+			color.ARGBFromHexMust("#D33881"): 2,
+			color.ARGBFromHexMust("#7F3F01"): 1,
+		}
+
+		ranked := Score(colorsToPopulation)
+		if len(ranked) != len(colorsToPopulation) {
+			t.Errorf("Expected %d colors, got %d",
+				len(colorsToPopulation), len(ranked))
+		}
+
+		for _, got := range ranked {
+			if _, ok := colorsToPopulation[got]; !ok {
+				t.Errorf("Expected one of the input colors, got %a", got)
+			}
+		}
+	})
 }
@@ -0,0 +1,116 @@
+package material
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/quantizer"
+)
+
+// streamChunkPixels is the number of pixels read from a stream at once
+const streamChunkPixels = 16 * 1024
+
+// PixelFormat is the layout of a pixel in a raw pixel stream.
+type PixelFormat int
+
+const (
+	// PixelFormatRGB24 is r, g, b bytes, e.g. ffmpeg -pix_fmt rgb24.
+	PixelFormatRGB24 PixelFormat = iota
+	// PixelFormatRGBA32 is r, g, b, a bytes with non-premultiplied alpha,
+	// e.g. ffmpeg -pix_fmt rgba.
+	PixelFormatRGBA32
+	// PixelFormatBGRA32 is b, g, r, a bytes with non-premultiplied alpha,
+	// e.g. ffmpeg -pix_fmt bgra.
+	PixelFormatBGRA32
+	// PixelFormatGray8 is a single gray byte, e.g. ffmpeg -pix_fmt gray.
+	PixelFormatGray8
+)
+
+// Size returns the number of bytes of a pixel. Returns 0 for unknown formats.
+func (f PixelFormat) Size() int {
+	switch f {
+	case PixelFormatRGB24:
+		return 3
+	case PixelFormatRGBA32, PixelFormatBGRA32:
+		return 4
+	case PixelFormatGray8:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// argb returns the color of the pixel p
+func (f PixelFormat) argb(p []byte) color.ARGB {
+	switch f {
+	case PixelFormatRGB24:
+		return color.ARGBFromRGB(p[0], p[1], p[2])
+	case PixelFormatRGBA32:
+		a := uint32(p[3])
+		return color.NewARGB(p[3],
+			premultiply(p[0], a), premultiply(p[1], a), premultiply(p[2], a))
+	case PixelFormatBGRA32:
+		a := uint32(p[3])
+		return color.NewARGB(p[3],
+			premultiply(p[2], a), premultiply(p[1], a), premultiply(p[0], a))
+	default:
+		return color.ARGBFromRGB(p[0], p[0], p[0])
+	}
+}
+
+// FromStream returns WeightedSource colors from a raw pixel stream of format,
+// e.g. the rawvideo output of ffmpeg. Unlike FromReader, the stream is not
+// kept in memory. It is read in chunks into a quantizer.Histogram until EOF,
+// and the colors of the histogram are returned with their populations as
+// weights. Pixels are weighted by alpha and incomplete trailing pixels are
+// ignored.
+//
+// ctx is checked between chunks. Returns ctx.Err() if context is Done.
+func FromStream(
+	ctx context.Context,
+	r io.Reader,
+	format PixelFormat,
+) WeightedSource {
+	return func() ([]color.ARGB, []float64, error) {
+		size := format.Size()
+		if size == 0 {
+			return nil, nil, fmt.Errorf("unknown pixel format %d", format)
+		}
+
+		h := quantizer.NewHistogram(quantizer.WithAlphaWeighting())
+		buf := make([]byte, size*streamChunkPixels)
+		pixels := make([]color.ARGB, 0, streamChunkPixels)
+		for {
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+
+			n, err := io.ReadFull(r, buf)
+			pixels = pixels[:0]
+			for i := 0; i+size <= n; i += size {
+				pixels = append(pixels, format.argb(buf[i:i+size]))
+			}
+			h.Add(pixels)
+
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		qm := h.Colors()
+		colors := slices.Sorted(maps.Keys(qm))
+		weights := make([]float64, len(colors))
+		for i, c := range colors {
+			weights[i] = float64(qm[c])
+		}
+		return colors, weights, nil
+	}
+}
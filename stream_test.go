@@ -0,0 +1,120 @@
+package material
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/quantizer"
+)
+
+func TestFromStream(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	pixels := make([]color.ARGB, 3*streamChunkPixels+5)
+	for i := range pixels {
+		pixels[i] = color.ARGB(0xFF000000 | r.Uint32())
+	}
+	gray := make([]color.ARGB, len(pixels))
+	for i, c := range pixels {
+		gray[i] = color.ARGBFromRGB(c.Red(), c.Red(), c.Red())
+	}
+
+	var rgb, rgba, bgra, gray8 []byte
+	for _, c := range pixels {
+		rgb = append(rgb, c.Red(), c.Green(), c.Blue())
+		rgba = append(rgba, c.Red(), c.Green(), c.Blue(), 0xFF)
+		bgra = append(bgra, c.Blue(), c.Green(), c.Red(), 0xFF)
+		gray8 = append(gray8, c.Red())
+	}
+
+	tests := []struct {
+		name   string
+		format PixelFormat
+		data   []byte
+		want   []color.ARGB
+	}{
+		{"rgb24", PixelFormatRGB24, rgb, pixels},
+		{"rgba32", PixelFormatRGBA32, rgba, pixels},
+		{"bgra32", PixelFormatBGRA32, bgra, pixels},
+		{"gray8", PixelFormatGray8, gray8, gray},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// trailing bytes of an incomplete pixel are ignored
+			trailing := make([]byte, tc.format.Size()-1)
+			data := append(slices.Clip(tc.data), trailing...)
+
+			ctx := context.Background()
+			colors, weights, err := FromStream(
+				ctx,
+				bytes.NewReader(data),
+				tc.format,
+			)()
+			if err != nil {
+				t.Fatalf("FromStream() failed: %v", err)
+			}
+
+			h := quantizer.NewHistogram(quantizer.WithAlphaWeighting())
+			h.Add(tc.want)
+			want := h.Colors()
+
+			got := quantizer.QuantizedMap{}
+			for i, c := range colors {
+				got[c] = int(weights[i])
+			}
+			if !maps.Equal(got, want) {
+				t.Errorf("FromStream() differs from the histogram of pixels")
+			}
+		})
+	}
+}
+
+func TestFromStreamAlpha(t *testing.T) {
+	data := []byte{
+		0xFF, 0x00, 0x00, 0xFF,
+		0x00, 0x00, 0xFF, 0x00,
+		0x00, 0x00, 0xFF, 0x00,
+	}
+	colors, weights, err := FromStream(
+		context.Background(),
+		bytes.NewReader(data),
+		PixelFormatRGBA32,
+	)()
+	if err != nil {
+		t.Fatalf("FromStream() failed: %v", err)
+	}
+
+	want := []color.ARGB{0xFFFF0000}
+	if !slices.Equal(colors, want) || !slices.Equal(weights, []float64{1}) {
+		t.Errorf("FromStream() = %v, %v, want %v, [1]", colors, weights, want)
+	}
+}
+
+func TestFromStreamErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	src := FromStream(ctx, bytes.NewReader(make([]byte, 30)), PixelFormatRGB24)
+	if _, _, err := src(); err != context.Canceled {
+		t.Errorf("FromStream() error = %v, want %v", err, context.Canceled)
+	}
+
+	src = FromStream(context.Background(), bytes.NewReader(nil), -1)
+	if _, _, err := src(); err == nil {
+		t.Errorf("FromStream() with unknown format returned nil error")
+	}
+
+	src = FromStream(
+		context.Background(),
+		bytes.NewReader([]byte{0x00, 0x44, 0xFF, 0xFF, 0x11, 0x00}),
+		PixelFormatRGB24,
+	)
+	if _, err := GenerateWeighted(src); err != nil {
+		t.Errorf("failed to generate colors: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+// Package templates renders generated material colors into arbitrary text
+// files (e.g. terminal, bar, editor or GTK configs) using text/template.
+//
+// Templates are executed with Data, which holds the light and dark colors of
+// the same source, so both variants can be rendered in one pass:
+//
+//	background = {{ .Dark.Background | hex }}
+//	foreground = {{ .Dark.OnBackground | hex }}
+//	accent     = {{ palette .Dark "primary" 35 | hex }}
+//	light_bg   = {{ .Light.Background | hexa }}
+package templates
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"text/template"
+
+	"github.com/Nadim147c/material/v3"
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/palettes"
+)
+
+// Data is the value templates are executed with.
+type Data struct {
+	// Light is the light variant of the generated colors
+	Light *material.Colors
+	// Dark is the dark variant of the generated colors
+	Dark *material.Colors
+}
+
+// NewData generates the light and dark variants of colors from src using the
+// given options. Dark mode option is ignored. The source color is selected
+// from src once and both variants are generated from it.
+func NewData(src material.Source, options ...material.Option) (*Data, error) {
+	light, err := material.Generate(
+		src,
+		slices.Concat(options, []material.Option{material.WithDark(false)})...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	source := light.Scheme.SourceColorHct.ToARGB()
+	dark, err := material.Generate(
+		material.FromARGB([]color.ARGB{source}),
+		slices.Concat(options, []material.Option{
+			material.WithDark(true),
+			material.WithCandidate(0),
+		})...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	dark.Candidates = light.Candidates
+
+	return &Data{Light: light, Dark: dark}, nil
+}
+
+// Funcs returns the helper functions available in templates:
+//
+//   - hex, hexa, hexargb: color as #RRGGBB, #RRGGBBAA and #AARRGGBB.
+//   - red, green, blue, alpha: 8-bit components of a color.
+//   - hct, oklch: color as color.Hct and color.OkLch.
+//   - hue, chroma, tone: HCT components of a color.
+//   - palette: color from a tonal palette of the scheme at the given tone.
+//     Palette names are primary, secondary, tertiary, neutral,
+//     neutral_variant and error.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"hex":     color.ARGB.HexRGB,
+		"hexa":    color.ARGB.HexRGBA,
+		"hexargb": color.ARGB.HexARGB,
+		"red":     color.ARGB.Red,
+		"green":   color.ARGB.Green,
+		"blue":    color.ARGB.Blue,
+		"alpha":   color.ARGB.Alpha,
+		"hct":     color.ARGB.ToHct,
+		"oklch":   color.ARGB.ToOkLch,
+		"hue":     func(c color.ARGB) float64 { return c.ToHct().Hue },
+		"chroma":  func(c color.ARGB) float64 { return c.ToHct().Chroma },
+		"tone":    func(c color.ARGB) float64 { return c.ToHct().Tone },
+		"palette": paletteTone,
+	}
+}
+
+// paletteTone returns the color of the named palette at given tone.
+func paletteTone(
+	colors *material.Colors,
+	name string,
+	tone float64,
+) (color.ARGB, error) {
+	if colors == nil || colors.Scheme == nil {
+		return 0, fmt.Errorf("palette %q: colors has no scheme", name)
+	}
+
+	var palette palettes.TonalPalette
+	switch name {
+	case "primary":
+		palette = colors.Scheme.PrimaryPalette
+	case "secondary":
+		palette = colors.Scheme.SecondaryPalette
+	case "tertiary":
+		palette = colors.Scheme.TertiaryPalette
+	case "neutral":
+		palette = colors.Scheme.NeutralPalette
+	case "neutral_variant":
+		palette = colors.Scheme.NeutralVariantPalette
+	case "error":
+		palette = colors.Scheme.ErrorPalette
+	default:
+		return 0, fmt.Errorf("unknown palette %q", name)
+	}
+
+	if tone < 0 || tone > 100 {
+		return 0, fmt.Errorf("palette %q: tone %v out of range [0, 100]",
+			name, tone)
+	}
+	return palette.Tone(tone), nil
+}
+
+// New returns a new template with given name and the helper functions from
+// Funcs.
+func New(name string) *template.Template {
+	return template.New(name).Funcs(Funcs())
+}
+
+// Render parses text as a template and executes it with data to w.
+func Render(w io.Writer, text string, data any) error {
+	tmpl, err := New("material").Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, data)
+}
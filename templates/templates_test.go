@@ -0,0 +1,113 @@
+package templates
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Nadim147c/material/v3"
+	"github.com/Nadim147c/material/v3/color"
+)
+
+func TestRender(t *testing.T) {
+	data, err := NewData(
+		material.FromHex("#0044FF"),
+		material.WithVariant(material.VariantTonalSpot),
+	)
+	if err != nil {
+		t.Fatalf("failed to generate data: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"hex", "{{ .Dark.Primary | hex }}", data.Dark.Primary.HexRGB()},
+		{"hexa", "{{ .Light.Primary | hexa }}", data.Light.Primary.HexRGBA()},
+		{
+			"hexargb",
+			"{{ .Light.Primary | hexargb }}",
+			data.Light.Primary.HexARGB(),
+		},
+		{"alpha", "{{ .Light.Surface | alpha }}", "255"},
+		{"black", "{{ palette .Dark \"neutral\" 0 | hex }}", "#000000"},
+		{"white", "{{ palette .Light \"primary\" 100 | hex }}", "#FFFFFF"},
+		{
+			"tone",
+			"{{ palette .Dark \"primary\" 35 | hex }}",
+			data.Dark.Scheme.PrimaryPalette.Tone(35).HexRGB(),
+		},
+		{
+			"components",
+			"{{ with .Dark.Primary }}{{ red . }},{{ green . }},{{ blue . }}{{ end }}",
+			strings.Join([]string{
+				strconv.Itoa(int(data.Dark.Primary.Red())),
+				strconv.Itoa(int(data.Dark.Primary.Green())),
+				strconv.Itoa(int(data.Dark.Primary.Blue())),
+			}, ","),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := Render(&sb, tc.template, data); err != nil {
+				t.Fatalf("Render(%q) failed: %v", tc.template, err)
+			}
+			if got := sb.String(); got != tc.want {
+				t.Errorf("Render(%q) = %q, want %q", tc.template, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderError(t *testing.T) {
+	data, err := NewData(material.FromHex("#0044FF"))
+	if err != nil {
+		t.Fatalf("failed to generate data: %v", err)
+	}
+
+	tests := []string{
+		"{{ palette .Dark \"unknown\" 50 }}",
+		"{{ palette .Dark \"primary\" 101 }}",
+		"{{ .Dark.Primary | unknown }}",
+	}
+
+	for _, tmpl := range tests {
+		if err := Render(&strings.Builder{}, tmpl, data); err == nil {
+			t.Errorf("Render(%q) returned nil error", tmpl)
+		}
+	}
+}
+
+func TestNewDataSource(t *testing.T) {
+	calls := 0
+	src := func() ([]color.ARGB, error) {
+		calls++
+		return []color.ARGB{0xFF0044FF, 0xFFFF4400, 0xFF00AA44}, nil
+	}
+
+	data, err := NewData(src)
+	if err != nil {
+		t.Fatalf("failed to generate data: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("source called %d times, want 1", calls)
+	}
+
+	light := data.Light.Scheme.SourceColorHct.ToARGB()
+	dark := data.Dark.Scheme.SourceColorHct.ToARGB()
+	if light != dark {
+		t.Errorf("dark source = %s, want %s", dark.HexRGB(), light.HexRGB())
+	}
+	if !data.Dark.Scheme.Dark || data.Light.Scheme.Dark {
+		t.Error("light and dark variants are swapped")
+	}
+	if len(data.Dark.Candidates) != len(data.Light.Candidates) {
+		t.Errorf(
+			"dark has %d candidates, want %d",
+			len(data.Dark.Candidates), len(data.Light.Candidates),
+		)
+	}
+}
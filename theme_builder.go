@@ -0,0 +1,317 @@
+package material
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/dynamic"
+	"github.com/Nadim147c/material/v3/internal/strcase"
+	"github.com/Nadim147c/material/v3/palettes"
+)
+
+// ThemeBuilderTones is the list of tones included in the palettes of Material
+// Theme Builder documents.
+var ThemeBuilderTones = []int{
+	0, 5, 10, 15, 20, 25, 30, 35, 40, 50, 60, 70, 80, 90, 95, 98, 99, 100,
+}
+
+// ThemeBuilder is a Material Theme Builder compatible JSON document.
+type ThemeBuilder struct {
+	Description    string                      `json:"description"`
+	Seed           color.ARGB                  `json:"seed"`
+	CoreColors     ThemeBuilderCoreColors      `json:"coreColors"`
+	ExtendedColors []ThemeBuilderExtendedColor `json:"extendedColors"`
+	Schemes        ThemeBuilderSchemes         `json:"schemes"`
+	Palettes       ThemeBuilderPalettes        `json:"palettes"`
+}
+
+// ThemeBuilderCoreColors is the key colors of the core palettes. Missing colors
+// are derived from the seed color.
+type ThemeBuilderCoreColors struct {
+	Primary        color.ARGB `json:"primary,omitzero"`
+	Secondary      color.ARGB `json:"secondary,omitzero"`
+	Tertiary       color.ARGB `json:"tertiary,omitzero"`
+	Error          color.ARGB `json:"error,omitzero"`
+	Neutral        color.ARGB `json:"neutral,omitzero"`
+	NeutralVariant color.ARGB `json:"neutralVariant,omitzero"`
+}
+
+// ThemeBuilderExtendedColor is a custom color. Harmonized colors are blended
+// with the primary color.
+type ThemeBuilderExtendedColor struct {
+	Name        string     `json:"name"`
+	Color       color.ARGB `json:"color"`
+	Description string     `json:"description"`
+	Harmonized  bool       `json:"harmonized"`
+}
+
+// ThemeBuilderScheme maps camel case role names to colors.
+type ThemeBuilderScheme map[string]color.ARGB
+
+// ThemeBuilderSchemes is the schemes of every mode and contrast level.
+type ThemeBuilderSchemes struct {
+	Light               ThemeBuilderScheme `json:"light"`
+	LightMediumContrast ThemeBuilderScheme `json:"light-medium-contrast"`
+	LightHighContrast   ThemeBuilderScheme `json:"light-high-contrast"`
+	Dark                ThemeBuilderScheme `json:"dark"`
+	DarkMediumContrast  ThemeBuilderScheme `json:"dark-medium-contrast"`
+	DarkHighContrast    ThemeBuilderScheme `json:"dark-high-contrast"`
+}
+
+// themeBuilderRoles is the snake case roles of Material Theme Builder schemes.
+var themeBuilderRoles = []string{
+	"primary",
+	"surface_tint",
+	"on_primary",
+	"primary_container",
+	"on_primary_container",
+	"secondary",
+	"on_secondary",
+	"secondary_container",
+	"on_secondary_container",
+	"tertiary",
+	"on_tertiary",
+	"tertiary_container",
+	"on_tertiary_container",
+	"error",
+	"on_error",
+	"error_container",
+	"on_error_container",
+	"background",
+	"on_background",
+	"surface",
+	"on_surface",
+	"surface_variant",
+	"on_surface_variant",
+	"outline",
+	"outline_variant",
+	"shadow",
+	"scrim",
+	"inverse_surface",
+	"inverse_on_surface",
+	"inverse_primary",
+	"primary_fixed",
+	"on_primary_fixed",
+	"primary_fixed_dim",
+	"on_primary_fixed_variant",
+	"secondary_fixed",
+	"on_secondary_fixed",
+	"secondary_fixed_dim",
+	"on_secondary_fixed_variant",
+	"tertiary_fixed",
+	"on_tertiary_fixed",
+	"tertiary_fixed_dim",
+	"on_tertiary_fixed_variant",
+	"surface_dim",
+	"surface_bright",
+	"surface_container_lowest",
+	"surface_container_low",
+	"surface_container",
+	"surface_container_high",
+	"surface_container_highest",
+}
+
+// ThemeBuilderPalette maps tones to colors.
+type ThemeBuilderPalette map[int]color.ARGB
+
+// ThemeBuilderPalettes is the core tonal palettes.
+type ThemeBuilderPalettes struct {
+	Primary        ThemeBuilderPalette `json:"primary"`
+	Secondary      ThemeBuilderPalette `json:"secondary"`
+	Tertiary       ThemeBuilderPalette `json:"tertiary"`
+	Neutral        ThemeBuilderPalette `json:"neutral"`
+	NeutralVariant ThemeBuilderPalette `json:"neutral-variant"`
+}
+
+// NewThemeBuilder generates a Material Theme Builder document from src.
+//
+// Source color is resolved once and used for the light and dark schemes of
+// standard, medium and high contrast level. Like Material Theme Builder, the
+// schemes use tonal spot variant with 2021 spec, so the document is imported
+// back by Settings. Dark, contrast, variant, version and spec settings of the
+// options are ignored. Custom colors are exported as extended colors and
+// blended custom colors are marked as harmonized.
+func NewThemeBuilder(src Source, options ...Option) (*ThemeBuilder, error) {
+	colors, err := src()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := newSettings(options)
+
+	seed, _, err := sourceColor(cfg, colors, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Theme Builder documents don't have additional roles.
+	cfg.Colors = nil
+	cfg.Dark = false
+	cfg.Contrast = 0
+	cfg.Variant = VariantTonalSpot
+	cfg.Version = Version2021
+	cfg.Spec = nil
+	light, err := newScheme(seed, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := func(dark bool, contrast float64) *dynamic.Scheme {
+		c := *cfg
+		c.Dark = dark
+		c.Contrast = contrast
+		// newScheme can't fail, the settings are checked by the light scheme.
+		s, _ := newScheme(seed, &c)
+		return s
+	}
+
+	tb := &ThemeBuilder{
+		Description: "TYPE: CUSTOM\nMaterial Theme Builder export",
+		Seed:        seed,
+		CoreColors: ThemeBuilderCoreColors{
+			Primary:        light.PrimaryPalette.KeyColor.ToARGB(),
+			Secondary:      light.SecondaryPalette.KeyColor.ToARGB(),
+			Tertiary:       light.TertiaryPalette.KeyColor.ToARGB(),
+			Error:          light.ErrorPalette.KeyColor.ToARGB(),
+			Neutral:        light.NeutralPalette.KeyColor.ToARGB(),
+			NeutralVariant: light.NeutralVariantPalette.KeyColor.ToARGB(),
+		},
+		ExtendedColors: []ThemeBuilderExtendedColor{},
+		Schemes: ThemeBuilderSchemes{
+			Light:               newThemeBuilderScheme(light),
+			LightMediumContrast: newThemeBuilderScheme(scheme(false, 0.5)),
+			LightHighContrast:   newThemeBuilderScheme(scheme(false, 1)),
+			Dark:                newThemeBuilderScheme(scheme(true, 0)),
+			DarkMediumContrast:  newThemeBuilderScheme(scheme(true, 0.5)),
+			DarkHighContrast:    newThemeBuilderScheme(scheme(true, 1)),
+		},
+		Palettes: ThemeBuilderPalettes{
+			Primary:        newThemeBuilderPalette(&light.PrimaryPalette),
+			Secondary:      newThemeBuilderPalette(&light.SecondaryPalette),
+			Tertiary:       newThemeBuilderPalette(&light.TertiaryPalette),
+			Neutral:        newThemeBuilderPalette(&light.NeutralPalette),
+			NeutralVariant: newThemeBuilderPalette(&light.NeutralVariantPalette),
+		},
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.Custom)) {
+		custom := cfg.Custom[name]
+		tb.ExtendedColors = append(tb.ExtendedColors, ThemeBuilderExtendedColor{
+			Name:       name,
+			Color:      custom.Color,
+			Harmonized: custom.Blend,
+		})
+	}
+
+	return tb, nil
+}
+
+// ReadThemeBuilder decodes a Material Theme Builder document from r.
+func ReadThemeBuilder(r io.Reader) (*ThemeBuilder, error) {
+	var tb ThemeBuilder
+	if err := json.NewDecoder(r).Decode(&tb); err != nil {
+		return nil, err
+	}
+	return &tb, nil
+}
+
+// Source returns the seed color as Source. Falls back to the primary core color
+// when the document has no seed.
+func (tb *ThemeBuilder) Source() Source {
+	seed := tb.Seed
+	if seed == 0 {
+		seed = tb.CoreColors.Primary
+	}
+	return FromARGB([]color.ARGB{seed})
+}
+
+// Settings returns the settings of the document. Like Material Theme Builder,
+// it uses tonal spot variant with 2021 spec. Extended colors are added as
+// custom colors and harmonized extended colors are blended with the primary
+// color by 0.5 ratio.
+//
+// Core colors are used as palettes. Palettes without a core color are created
+// from the most chromatic tone of the palette in the document.
+func (tb *ThemeBuilder) Settings() Settings {
+	cfg := newSettings([]Option{
+		WithVariant(VariantTonalSpot),
+		WithVersion(Version2021),
+	})
+
+	for _, ext := range tb.ExtendedColors {
+		if cfg.Custom == nil {
+			cfg.Custom = map[string]CustomColorOption{}
+		}
+		custom := CustomColorOption{Color: ext.Color}
+		if ext.Harmonized {
+			custom.Blend = true
+			custom.Ratio = 0.5
+		}
+		cfg.Custom[ext.Name] = custom
+	}
+
+	core := tb.CoreColors
+	cfg.Palettes = Palettes{
+		Primary:   themeBuilderPalette(core.Primary, tb.Palettes.Primary),
+		Secondary: themeBuilderPalette(core.Secondary, tb.Palettes.Secondary),
+		Tertiary:  themeBuilderPalette(core.Tertiary, tb.Palettes.Tertiary),
+		Error:     themeBuilderPalette(core.Error, nil),
+		Neutral:   themeBuilderPalette(core.Neutral, tb.Palettes.Neutral),
+		NeutralVariant: themeBuilderPalette(
+			core.NeutralVariant,
+			tb.Palettes.NeutralVariant,
+		),
+	}
+
+	return *cfg
+}
+
+// newThemeBuilderScheme returns the themeBuilderRoles of scheme.
+func newThemeBuilderScheme(scheme *dynamic.Scheme) ThemeBuilderScheme {
+	colors := scheme.ToColorMap()
+	s := make(ThemeBuilderScheme, len(themeBuilderRoles))
+	for _, name := range themeBuilderRoles {
+		if dc := colors[name]; dc != nil {
+			s[strcase.Camel(name)] = dc.GetArgb(scheme)
+		}
+	}
+	return s
+}
+
+// newThemeBuilderPalette returns the ThemeBuilderTones of tp.
+func newThemeBuilderPalette(tp *palettes.TonalPalette) ThemeBuilderPalette {
+	p := make(ThemeBuilderPalette, len(ThemeBuilderTones))
+	for _, tone := range ThemeBuilderTones {
+		p[tone] = tp.Tone(float64(tone))
+	}
+	return p
+}
+
+// themeBuilderPalette creates a tonal palette from the core color. If core
+// color is missing, the most chromatic color of the palette is used instead.
+// Returns nil if both are missing.
+func themeBuilderPalette(
+	core color.ARGB,
+	palette ThemeBuilderPalette,
+) *palettes.TonalPalette {
+	if core != 0 {
+		return palettes.NewFromARGB(core)
+	}
+
+	var key color.Hct
+	found := false
+	for _, tone := range slices.Sorted(maps.Keys(palette)) {
+		hct := palette[tone].ToHct()
+		if !found || hct.Chroma > key.Chroma {
+			key = hct
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return palettes.NewFromHct(key)
+}
@@ -0,0 +1,167 @@
+package material
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/internal/strcase"
+)
+
+func TestNewThemeBuilder(t *testing.T) {
+	tb, err := NewThemeBuilder(
+		FromHex("#0044FF"),
+		WithVariant(VariantTonalSpot),
+		WithCustomColor("success", color.ARGBFromHexMust("#00FF00")),
+		WithCustomColorBlend("warning", color.ARGBFromHexMust("#FFAA00"), 0.5),
+	)
+	if err != nil {
+		t.Fatalf("NewThemeBuilder() failed: %v", err)
+	}
+
+	b, err := json.Marshal(tb)
+	if err != nil {
+		t.Fatalf("failed to encode theme: %v", err)
+	}
+
+	var doc struct {
+		Seed           string `json:"seed"`
+		CoreColors     map[string]string
+		ExtendedColors []map[string]any
+		Schemes        map[string]map[string]string
+		Palettes       map[string]map[string]string
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("failed to decode theme: %v", err)
+	}
+
+	if doc.Seed != "#0044FF" {
+		t.Errorf("seed = %q, want %q", doc.Seed, "#0044FF")
+	}
+	if doc.CoreColors["primary"] == "" {
+		t.Error("core colors has no primary color")
+	}
+
+	if len(doc.ExtendedColors) != 2 {
+		t.Fatalf("got %d extended colors, want 2", len(doc.ExtendedColors))
+	}
+	if doc.ExtendedColors[1]["name"] != "warning" ||
+		doc.ExtendedColors[1]["harmonized"] != true {
+		t.Errorf("unexpected extended color: %v", doc.ExtendedColors[1])
+	}
+
+	schemes := []string{
+		"light", "light-medium-contrast", "light-high-contrast",
+		"dark", "dark-medium-contrast", "dark-high-contrast",
+	}
+	for _, name := range schemes {
+		s := doc.Schemes[name]
+		for _, role := range []string{"primary", "inverseOnSurface"} {
+			if s[role] == "" {
+				t.Errorf("scheme %s has no %s", name, role)
+			}
+		}
+		if _, ok := s["primaryPaletteKeyColor"]; ok {
+			t.Errorf("scheme %s contains palette key color", name)
+		}
+	}
+
+	for _, name := range []string{"primary", "neutral-variant"} {
+		p := doc.Palettes[name]
+		if len(p) != len(ThemeBuilderTones) {
+			t.Errorf("palette %s has %d tones, want %d",
+				name, len(p), len(ThemeBuilderTones))
+		}
+		if p["0"] != "#000000" || p["100"] != "#FFFFFF" {
+			t.Errorf("palette %s has wrong tones: %v", name, p)
+		}
+	}
+}
+
+func TestThemeBuilderRoundTrip(t *testing.T) {
+	tb, err := NewThemeBuilder(
+		FromHex("#0044FF"),
+		WithVariant(VariantTonalSpot),
+		WithVersion(Version2021),
+		WithCustomColorBlend("warning", color.ARGBFromHexMust("#FFAA00"), 0.5),
+	)
+	if err != nil {
+		t.Fatalf("NewThemeBuilder() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(tb); err != nil {
+		t.Fatalf("failed to encode theme: %v", err)
+	}
+
+	imported, err := ReadThemeBuilder(&buf)
+	if err != nil {
+		t.Fatalf("ReadThemeBuilder() failed: %v", err)
+	}
+
+	cfg := imported.Settings()
+	if !cfg.Custom["warning"].Blend {
+		t.Error("harmonized color is not imported as blended custom color")
+	}
+	if cfg.Palettes.Secondary == nil || cfg.Palettes.NeutralVariant == nil {
+		t.Fatal("palettes are not imported")
+	}
+
+	for _, dark := range []bool{false, true} {
+		colors, err := Generate(
+			imported.Source(),
+			WithSettings(cfg),
+			WithDark(dark),
+		)
+		if err != nil {
+			t.Fatalf("failed to generate colors: %v", err)
+		}
+
+		want := tb.Schemes.Light
+		if dark {
+			want = tb.Schemes.Dark
+		}
+		for role, got := range map[string]color.ARGB{
+			"primary":            colors.Primary,
+			"secondaryContainer": colors.SecondaryContainer,
+			"surface":            colors.Surface,
+			"onSurfaceVariant":   colors.OnSurfaceVariant,
+		} {
+			if !closeColors(got, want[role]) {
+				t.Errorf("dark=%v %s = %s, want %s", dark, role, got, want[role])
+			}
+		}
+	}
+}
+
+func TestThemeBuilderSettings(t *testing.T) {
+	tb, err := NewThemeBuilder(FromHex("#0044FF"))
+	if err != nil {
+		t.Fatalf("NewThemeBuilder() failed: %v", err)
+	}
+	if _, ok := tb.Schemes.Light["primaryDim"]; ok {
+		t.Error("scheme contains dim role")
+	}
+
+	colors, err := Generate(tb.Source(), WithSettings(tb.Settings()))
+	if err != nil {
+		t.Fatalf("failed to generate colors: %v", err)
+	}
+
+	got := colors.Map()
+	for role, want := range tb.Schemes.Light {
+		argb := got[strcase.Snake(role)]
+		if !closeColors(argb, want) {
+			t.Errorf("%s = %s, want %s", role, argb, want)
+		}
+	}
+}
+
+// closeColors reports whether every channel of a and b differs at most by 2.
+func closeColors(a, b color.ARGB) bool {
+	diff := func(x, y uint8) bool { return max(x, y)-min(x, y) <= 2 }
+	return diff(a.Red(), b.Red()) &&
+		diff(a.Green(), b.Green()) &&
+		diff(a.Blue(), b.Blue())
+}
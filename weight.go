@@ -0,0 +1,102 @@
+package material
+
+import (
+	"errors"
+	"image"
+	gocolor "image/color"
+	"math"
+	"slices"
+
+	"github.com/Nadim147c/material/v3/color"
+)
+
+var errUnweightedQuantizer = errors.New(
+	"quantizer doesn't implement quantizer.WeightedQuantizer",
+)
+
+// WeightedSource is a Source which also returns the weight of each color. The
+// weight of colors[i] is weights[i]. Colors with weight of 0 or less are
+// ignored.
+type WeightedSource func() (colors []color.ARGB, weights []float64, err error)
+
+// WeightFunc returns the weight of the pixel at (x, y)
+type WeightFunc func(x, y int) float64
+
+// RectWeight returns a WeightFunc which weights the pixels inside r by 1 and
+// the rest by outside.
+func RectWeight(r image.Rectangle, outside float64) WeightFunc {
+	return func(x, y int) float64 {
+		if image.Pt(x, y).In(r) {
+			return 1
+		}
+		return outside
+	}
+}
+
+// RadialWeight returns a WeightFunc which weights the pixel at the center of r
+// by 1. The weight decreases linearly with the distance from the center down
+// to edge at the corners of r and outside of r.
+func RadialWeight(r image.Rectangle, edge float64) WeightFunc {
+	cx := float64(r.Min.X+r.Max.X) / 2
+	cy := float64(r.Min.Y+r.Max.Y) / 2
+	radius := math.Hypot(float64(r.Dx()), float64(r.Dy())) / 2
+	return func(x, y int) float64 {
+		if radius == 0 {
+			return edge
+		}
+		d := math.Hypot(float64(x)+0.5-cx, float64(y)+0.5-cy) / radius
+		return 1 - (1-edge)*min(d, 1)
+	}
+}
+
+// MaskWeight returns a WeightFunc which weights the pixels by the gray level of
+// mask in range [0, 1], e.g. a saliency map. Translucent mask pixels are
+// darkened by their alpha, so *image.Alpha can be used as mask. Pixels outside
+// the bounds of mask have weight of 0.
+func MaskWeight(mask image.Image) WeightFunc {
+	bounds := mask.Bounds()
+	if gray, ok := mask.(*image.Gray); ok {
+		return func(x, y int) float64 {
+			if !image.Pt(x, y).In(bounds) {
+				return 0
+			}
+			return float64(gray.Pix[gray.PixOffset(x, y)]) / 0xFF
+		}
+	}
+	return func(x, y int) float64 {
+		if !image.Pt(x, y).In(bounds) {
+			return 0
+		}
+		gray := gocolor.Gray16Model.Convert(mask.At(x, y)).(gocolor.Gray16)
+		return float64(gray.Y) / 0xFFFF
+	}
+}
+
+// FromWeightedImage returns WeightedSource colors from img weighted by weight.
+// Options are same as FromImage. Downscaled pixels are weighted by the average
+// weight of the pixels they represent.
+func FromWeightedImage(
+	img image.Image,
+	weight WeightFunc,
+	options ...ImageOption,
+) WeightedSource {
+	var opts imageOptions
+	for opt := range slices.Values(options) {
+		opt(&opts)
+	}
+
+	return func() ([]color.ARGB, []float64, error) {
+		pixels, weights := readImage(img, opts, weight)
+		return pixels, weights, nil
+	}
+}
+
+// GenerateWeighted generates material you colors from weighted source colors.
+// The quantizer must implement quantizer.WeightedQuantizer.
+func GenerateWeighted(src WeightedSource, options ...Option) (*Colors, error) {
+	colors, weights, err := src()
+	if err != nil {
+		return nil, err
+	}
+	return generate(colors, weights, newSettings(options))
+}
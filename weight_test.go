@@ -0,0 +1,141 @@
+package material
+
+import (
+	"context"
+	"errors"
+	"image"
+	gocolor "image/color"
+	"math"
+	"testing"
+
+	"github.com/Nadim147c/material/v3/color"
+	"github.com/Nadim147c/material/v3/quantizer"
+)
+
+func TestRadialWeight(t *testing.T) {
+	weight := RadialWeight(image.Rect(0, 0, 100, 100), 0.25)
+
+	tests := []struct {
+		x, y int
+		want float64
+	}{
+		{49, 49, 1 - 0.75*0.01},
+		{0, 0, 0.25 + 0.75*0.01},
+		{200, 200, 0.25},
+	}
+
+	for _, tc := range tests {
+		got := weight(tc.x, tc.y)
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("weight(%d, %d) = %f, want %f", tc.x, tc.y, got, tc.want)
+		}
+	}
+}
+
+func TestMaskWeight(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 2, 1))
+	gray.SetGray(0, 0, gocolor.Gray{Y: 0xFF})
+	gray.SetGray(1, 0, gocolor.Gray{Y: 0x33})
+
+	alpha := image.NewAlpha(gray.Bounds())
+	alpha.SetAlpha(0, 0, gocolor.Alpha{A: 0xFF})
+	alpha.SetAlpha(1, 0, gocolor.Alpha{A: 0x33})
+
+	for name, mask := range map[string]image.Image{
+		"gray":  gray,
+		"alpha": alpha,
+	} {
+		t.Run(name, func(t *testing.T) {
+			weight := MaskWeight(mask)
+			for _, tc := range []struct {
+				x, y int
+				want float64
+			}{{0, 0, 1}, {1, 0, 0.2}, {2, 0, 0}} {
+				got := weight(tc.x, tc.y)
+				if math.Abs(got-tc.want) > 1e-9 {
+					t.Errorf("weight(%d, %d) = %f, want %f",
+						tc.x, tc.y, got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFromWeightedImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := range 2 {
+		for x := range 4 {
+			img.SetRGBA(x, y, gocolor.RGBA{R: uint8(x * 50), A: 255})
+		}
+	}
+	weight := RectWeight(image.Rect(0, 0, 1, 2), 0.5)
+
+	pixels, weights, err := FromWeightedImage(img, weight)()
+	if err != nil {
+		t.Fatalf("FromWeightedImage() failed: %v", err)
+	}
+	if len(pixels) != 8 || len(weights) != 8 {
+		t.Fatalf("FromWeightedImage() returned %d pixels and %d weights",
+			len(pixels), len(weights))
+	}
+	for i, w := range weights {
+		want := 0.5
+		if i%4 == 0 {
+			want = 1
+		}
+		if w != want {
+			t.Errorf("weights[%d] = %f, want %f", i, w, want)
+		}
+	}
+
+	pixels, weights, _ = FromWeightedImage(img, weight, WithMaxPixels(2))()
+	want := []float64{0.75, 0.5}
+	if len(pixels) != 2 || weights[0] != want[0] || weights[1] != want[1] {
+		t.Errorf("FromWeightedImage() with max pixels weights = %v, want %v",
+			weights, want)
+	}
+}
+
+func TestGenerateWeighted(t *testing.T) {
+	// The red region is small, but only the region is weighted.
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	red := gocolor.RGBA{R: 200, G: 30, B: 30, A: 255}
+	blue := gocolor.RGBA{R: 30, G: 60, B: 200, A: 255}
+	region := image.Rect(16, 16, 32, 32)
+	for y := range 64 {
+		for x := range 64 {
+			if image.Pt(x, y).In(region) {
+				img.SetRGBA(x, y, red)
+			} else {
+				img.SetRGBA(x, y, blue)
+			}
+		}
+	}
+
+	src := FromWeightedImage(img, RectWeight(region, 0))
+	colors, err := GenerateWeighted(src, WithSeed(1))
+	if err != nil {
+		t.Fatalf("failed to generate colors: %v", err)
+	}
+
+	want := color.ARGBFromInterface(red)
+	if got := colors.Candidates[0].Color; got != want {
+		t.Errorf("source color = %s, want %s", got, want)
+	}
+	if got := colors.Candidates[0].Population; got != region.Dx()*region.Dy() {
+		t.Errorf("population = %d, want %d", got, region.Dx()*region.Dy())
+	}
+
+	q := quantizer.Func(func(
+		ctx context.Context,
+		pixels []color.ARGB,
+		maxColors int,
+	) (quantizer.QuantizedMap, error) {
+		return quantizer.Wu{}.Quantize(ctx, pixels, maxColors)
+	})
+	_, err = GenerateWeighted(src, WithQuantizer(q))
+	if !errors.Is(err, errUnweightedQuantizer) {
+		t.Errorf("GenerateWeighted() error = %v, want %v",
+			err, errUnweightedQuantizer)
+	}
+}